@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/codec"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+// Result is the measured cost of one codec over the chunks of the catalog
+type Result struct {
+	Codec           string
+	Bytes           int64   // Encoded size of all chunks
+	BytesPerHotel   float64 // Bytes / hotels
+	MarshalUs       float64 // Per chunk
+	UnmarshalUs     float64 // Per chunk
+	MarshalAllocs   float64 // Per chunk
+	UnmarshalAllocs float64 // Per chunk
+	RoundTrip       bool    // Every chunk decoded equal to the original
+}
+
+func main() {
+	var dataPaths catalog.PathList
+	flag.Var(&dataPaths, "data", "data file or directory of *.json files; repeat or comma-separate to merge several (default: $"+catalog.EnvDataPath+", then ./data.json probes)")
+	chunkSize := flag.Int("chunkSize", catalog.DefaultChunkSize, "hotels per chunk, as in GetHotelsStreaming")
+	duration := flag.Duration("duration", time.Second, "how long to marshal, then unmarshal, the chunks with each codec")
+	codecs := flag.String("codecs", strings.Join(codec.Names, ","), "comma-separated codecs to measure")
+	flag.Parse()
+
+	var names []string
+	for _, value := range strings.Split(*codecs, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		name, err := codec.Parse(value)
+		if err != nil {
+			log.Fatalf("Invalid -codecs: %v", err)
+		}
+		names = append(names, name)
+	}
+	if *chunkSize <= 0 {
+		log.Fatalf("Invalid -chunkSize: %d is not positive", *chunkSize)
+	}
+
+	source, err := catalog.Resolve(dataPaths, os.Getenv(catalog.EnvDataPath))
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+	log.Printf("Using data source: %s", source)
+	cat, err := source.Load()
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+
+	var chunks []*pb.HotelChunk
+	err = cat.Stream(&pb.StreamRequest{ChunkSize: int32(*chunkSize)}, func(chunk *pb.HotelChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to chunk data: %v", err)
+	}
+	log.Printf("Loaded %d hotels, %d chunks of up to %d", len(cat.Hotels), len(chunks), *chunkSize)
+
+	var results []Result
+	for _, name := range names {
+		result, err := measure(codec.Get(name), chunks, len(cat.Hotels), *duration)
+		if err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+		if !result.RoundTrip {
+			log.Printf("%s: decoded chunks differ from the originals", name)
+		}
+		results = append(results, result)
+	}
+
+	printResults(len(cat.Hotels), len(chunks), *chunkSize, results)
+}
+
+// measure marshals the chunks with c, then unmarshals them, each for at
+// least d
+func measure(c encoding.Codec, chunks []*pb.HotelChunk, hotels int, d time.Duration) (Result, error) {
+	result := Result{Codec: c.Name(), RoundTrip: true}
+
+	encoded := make([][]byte, len(chunks))
+	for i, chunk := range chunks {
+		data, err := c.Marshal(chunk)
+		if err != nil {
+			return Result{}, err
+		}
+		encoded[i] = data
+		result.Bytes += int64(len(data))
+
+		decoded := &pb.HotelChunk{}
+		if err := c.Unmarshal(data, decoded); err != nil {
+			return Result{}, err
+		}
+		if !proto.Equal(chunk, decoded) {
+			result.RoundTrip = false
+		}
+	}
+	if hotels > 0 {
+		result.BytesPerHotel = float64(result.Bytes) / float64(hotels)
+	}
+
+	var err error
+	result.MarshalUs, result.MarshalAllocs = repeat(d, len(chunks), func(i int) {
+		if _, marshalErr := c.Marshal(chunks[i]); marshalErr != nil {
+			err = marshalErr
+		}
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	result.UnmarshalUs, result.UnmarshalAllocs = repeat(d, len(encoded), func(i int) {
+		if unmarshalErr := c.Unmarshal(encoded[i], &pb.HotelChunk{}); unmarshalErr != nil {
+			err = unmarshalErr
+		}
+	})
+	return result, err
+}
+
+// repeat calls fn over the n chunks, round after round, until d has
+// elapsed. It returns the mean time and heap allocations per call.
+func repeat(d time.Duration, n int, fn func(i int)) (us, allocs float64) {
+	if n == 0 {
+		return 0, 0
+	}
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	calls := 0
+	start := time.Now()
+	for calls == 0 || time.Since(start) < d {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		calls += n
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return float64(elapsed.Microseconds()) / float64(calls), float64(after.Mallocs-before.Mallocs) / float64(calls)
+}
+
+// printResults writes the results as a table, sizes relative to the first
+func printResults(hotels, chunks, chunkSize int, results []Result) {
+	fmt.Printf("Hotels:      %d\n", hotels)
+	fmt.Printf("Chunks:      %d of up to %d hotels\n\n", chunks, chunkSize)
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "codec\tMB\tbytes/hotel\tsize\tmarshal µs/chunk\tunmarshal µs/chunk\tmarshal MB/s\tunmarshal MB/s\tallocs/marshal\tallocs/unmarshal\tround trip\t")
+	for _, result := range results {
+		size := 100 * float64(result.Bytes) / float64(results[0].Bytes)
+		megabytes := float64(result.Bytes) / (1024 * 1024)
+		roundTrip := "ok"
+		if !result.RoundTrip {
+			roundTrip = "lossy"
+		}
+		fmt.Fprintf(table, "%s\t%.2f\t%.0f\t%.0f%%\t%.1f\t%.1f\t%.1f\t%.1f\t%.0f\t%.0f\t%s\t\n",
+			result.Codec, megabytes, result.BytesPerHotel, size,
+			result.MarshalUs, result.UnmarshalUs,
+			throughput(megabytes/float64(chunks), result.MarshalUs), throughput(megabytes/float64(chunks), result.UnmarshalUs),
+			result.MarshalAllocs, result.UnmarshalAllocs, roundTrip)
+	}
+	table.Flush()
+}
+
+// throughput converts the time to process one chunk of the given size to MB/s
+func throughput(megabytesPerChunk, us float64) float64 {
+	if us == 0 {
+		return 0
+	}
+	return megabytesPerChunk / (us / 1e6)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-vs-http/internal/datagen"
+	pb "grpc-vs-http/proto"
+)
+
+func main() {
+	cfg := datagen.DefaultConfig()
+
+	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "random seed; the same seed and knobs reproduce the same file")
+	flag.IntVar(&cfg.Hotels, "hotels", cfg.Hotels, "number of hotels (upper bound when -size is set, 0 = unbounded)")
+	flag.IntVar(&cfg.RoomsPerHotel, "rooms", cfg.RoomsPerHotel, "rooms per hotel")
+	flag.IntVar(&cfg.RatesPerRoom, "rates", cfg.RatesPerRoom, "rates per room")
+	flag.IntVar(&cfg.Reviews, "reviews", cfg.Reviews, "reviews per hotel")
+	flag.IntVar(&cfg.Photos, "photos", cfg.Photos, "photos per hotel")
+	flag.BoolVar(&cfg.Minimal, "minimal", cfg.Minimal, "only ids, name, location, rate and availability per hotel (automatic when -size is below one full hotel)")
+	size := flag.String("size", "", "add hotels while the file stays within this size, e.g. 100KB, 50MB, 2GB")
+	generatedAt := flag.String("generated-at", "", "RFC 3339 timestamp for the metadata (default: now); set it for byte-identical output")
+	out := flag.String("out", "data.json", "output file, or - for stdout")
+	flag.Parse()
+
+	if *size != "" {
+		maxBytes, err := parseSize(*size)
+		if err != nil {
+			log.Fatalf("Invalid -size: %v", err)
+		}
+		cfg.MaxBytes = maxBytes
+		if !isFlagSet("hotels") {
+			cfg.Hotels = 0
+		}
+	}
+	if *generatedAt != "" {
+		t, err := time.Parse(time.RFC3339, *generatedAt)
+		if err != nil {
+			log.Fatalf("Invalid -generated-at: %v", err)
+		}
+		cfg.GeneratedAt = t
+	}
+
+	start := time.Now()
+	progress := func(hotels int, bytes int64) {
+		if hotels%10000 == 0 {
+			log.Printf("Generated %d hotels (%.2f MB)...", hotels, float64(bytes)/(1024*1024))
+		}
+	}
+
+	var metadata *pb.Metadata
+	var err error
+	if *out == "-" {
+		metadata, err = datagen.Write(os.Stdout, cfg, progress)
+	} else {
+		metadata, err = writeFile(*out, cfg, progress)
+	}
+	if err != nil {
+		log.Fatalf("Failed to generate data: %v", err)
+	}
+
+	log.Printf("Generated %d hotels (%.2f MB) to %s in %s",
+		metadata.ActualHotels, metadata.ActualSizeMB, *out, time.Since(start).Round(time.Millisecond))
+}
+
+// writeFile generates the dataset into a temporary file next to path and
+// renames it over path once complete, so a failed run leaves an existing
+// file untouched
+func writeFile(path string, cfg datagen.Config, progress func(hotels int, bytes int64)) (*pb.Metadata, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name()) // No-op once renamed
+
+	metadata, err := datagen.Write(file, cfg, progress)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	// Written data may only reach the disk, and fail to, on close
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// parseSize parses a byte count with an optional KB, MB or GB suffix (powers of 1024)
+func parseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not a positive size", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"grpc-vs-http/internal/latency"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamDetail breaks the time of one stream down, for /stats?detail=true
+type StreamDetail struct {
+	Chunks             int              `json:"chunks"`
+	TimeToFirstChunkUs int64            `json:"timeToFirstChunkUs"`
+	WireBytes          int64            `json:"wireBytes"`              // Chunks as received: JSON lines for http-json, protobuf otherwise
+	DecodedBytes       int64            `json:"decodedBytes"`           // Protobuf size of the decoded chunks
+	DecodeTimeUs       int64            `json:"decodeTimeUs"`           // Total time spent decoding chunks
+	ChunkGaps          *latency.Summary `json:"chunkGaps,omitempty"`    // Time between consecutive chunks
+	ChunkDecodes       *latency.Summary `json:"chunkDecodes,omitempty"` // Decode time of each chunk
+}
+
+// chunkTrace accumulates the size and decode time of the messages a
+// HotelSource receives. Sources find it in the context of the call and
+// record into it from the goroutine that calls fn; a nil trace records
+// nothing.
+type chunkTrace struct {
+	wireBytes int64
+	decode    time.Duration
+
+	// Recorded by traceStatsHandler, for gRPC sources only
+	compressedBytes   int64
+	uncompressedBytes int64
+}
+
+type chunkTraceKey struct{}
+
+// withChunkTrace returns a context that makes sources record into trace
+func withChunkTrace(ctx context.Context, trace *chunkTrace) context.Context {
+	return context.WithValue(ctx, chunkTraceKey{}, trace)
+}
+
+// chunkTraceFrom returns the trace of ctx, or nil
+func chunkTraceFrom(ctx context.Context) *chunkTrace {
+	trace, _ := ctx.Value(chunkTraceKey{}).(*chunkTrace)
+	return trace
+}
+
+// decoded records one message of n bytes that took d to decode
+func (t *chunkTrace) decoded(n int, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.wireBytes += int64(n)
+	t.decode += d
+}
+
+// received records one gRPC message, as sent and after decompression
+func (t *chunkTrace) received(compressed, uncompressed int) {
+	if t == nil {
+		return
+	}
+	t.compressedBytes += int64(compressed)
+	t.uncompressedBytes += int64(uncompressed)
+}
+
+// traceStatsHandler records the compressed and uncompressed size of the
+// messages received by traced gRPC calls
+type traceStatsHandler struct{}
+
+func (traceStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (traceStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InPayload); ok {
+		chunkTraceFrom(ctx).received(in.CompressedLength, in.Length)
+	}
+}
+
+func (traceStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (traceStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// traceCodec wraps a codec, timing every Unmarshal into a trace. It is both
+// a gRPC codec (grpc.ForceCodec) and a Connect codec (connect.WithCodec);
+// its name is the wrapped codec's, so gRPC still sends it as the
+// content-subtype.
+type traceCodec struct {
+	trace *chunkTrace
+	codec encoding.Codec
+}
+
+func (c traceCodec) Name() string {
+	return c.codec.Name()
+}
+
+func (c traceCodec) Marshal(v any) ([]byte, error) {
+	return c.codec.Marshal(v)
+}
+
+func (c traceCodec) Unmarshal(data []byte, v any) error {
+	start := time.Now()
+	err := c.codec.Unmarshal(data, v)
+	c.trace.decoded(len(data), time.Since(start))
+	return err
+}
+
+// detailRecorder builds a StreamDetail as the chunks of a stream arrive
+type detailRecorder struct {
+	trace   *chunkTrace
+	start   time.Time
+	last    time.Time     // Arrival of the previous chunk
+	decoded time.Duration // trace.decode at the previous chunk
+	gaps    *latency.Histogram
+	decodes *latency.Histogram
+	detail  StreamDetail
+}
+
+func newDetailRecorder(start time.Time, trace *chunkTrace) *detailRecorder {
+	return &detailRecorder{
+		trace:   trace,
+		start:   start,
+		gaps:    latency.NewHistogram(),
+		decodes: latency.NewHistogram(),
+	}
+}
+
+// chunk records the arrival of one chunk, and what the source traced
+// while receiving it
+func (r *detailRecorder) chunk(chunk *pb.HotelChunk) {
+	now := time.Now()
+	d := &r.detail
+	if d.Chunks == 0 {
+		d.TimeToFirstChunkUs = now.Sub(r.start).Microseconds()
+	} else {
+		r.gaps.Record(now.Sub(r.last))
+	}
+	r.last = now
+	d.Chunks++
+	d.DecodedBytes += int64(proto.Size(chunk))
+
+	// inproc has nothing to decode and traces nothing
+	if r.trace.wireBytes > d.WireBytes {
+		r.decodes.Record(r.trace.decode - r.decoded)
+	}
+	r.decoded = r.trace.decode
+	d.WireBytes = r.trace.wireBytes
+}
+
+// finish returns the detail of the whole stream
+func (r *detailRecorder) finish() *StreamDetail {
+	d := r.detail
+	d.DecodeTimeUs = r.trace.decode.Microseconds()
+	d.ChunkGaps = r.gaps.Summary()
+	d.ChunkDecodes = r.decodes.Summary()
+	return &d
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"grpc-vs-http/internal/hotelquery"
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxChunkSize bounds a single protobuf chunk read from the HTTP
+// microservice, matching the gRPC client's MaxCallRecvMsgSize
+const maxChunkSize = 1000 * 1024 * 1024
+
+// streamErrorTrailer carries the error of an HTTP microservice stream that
+// failed after its first chunk
+const streamErrorTrailer = "X-Stream-Error"
+
+// chunkLine is one line of the HTTP microservice's NDJSON stream. A stream
+// that fails after it started ends with a line carrying only an error.
+type chunkLine struct {
+	*pb.HotelChunk
+	Error string `json:"error,omitempty"`
+}
+
+// newHTTPClient creates the client used for the HTTP microservice. The
+// default transport keeps only two idle connections per host, which would
+// make /concurrent-stats reconnect on most calls. Response bodies are
+// counted into the wire counter of the request, if any.
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 200
+	transport.MaxIdleConnsPerHost = 200
+	return &http.Client{Transport: metrics.WireTransport(transport)}
+}
+
+// httpSource reads chunks from GET /data/chunked on the HTTP microservice,
+// as NDJSON or as length-delimited protobuf. HTTP errors are returned as
+// gRPC status errors so every transport is reported alike.
+type httpSource struct {
+	client  *http.Client
+	baseURL string
+	proto   bool // Request application/x-protobuf instead of NDJSON
+}
+
+func (s httpSource) Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error {
+	url := s.baseURL + "/data/chunked?" + hotelquery.EncodeStreamRequest(req).Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if s.proto {
+		httpReq.Header.Set("Accept", "application/x-protobuf")
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if isDial(err) {
+			return dialError{st: status.New(codes.Unavailable, err.Error())}
+		}
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpError(resp)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	next := readNDJSONChunk
+	if s.proto {
+		next = readProtoChunk
+	}
+	trace := chunkTraceFrom(ctx)
+	for {
+		chunk, err := next(reader, trace)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return err
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+
+	// Trailers are only available once the body has been read to the end
+	if message := resp.Trailer.Get(streamErrorTrailer); message != "" {
+		return status.Error(codes.Internal, message)
+	}
+	return nil
+}
+
+// readNDJSONChunk decodes the next line of an NDJSON stream
+func readNDJSONChunk(reader *bufio.Reader, trace *chunkTrace) (*pb.HotelChunk, error) {
+	line, err := reader.ReadBytes('\n')
+	if err == io.EOF && len(line) == 0 {
+		return nil, io.EOF
+	}
+	if err != nil && err != io.EOF {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	var chunk chunkLine
+	start := time.Now()
+	err = json.Unmarshal(line, &chunk)
+	trace.decoded(len(line), time.Since(start))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "decode chunk: %v", err)
+	}
+	if chunk.Error != "" {
+		return nil, status.Error(codes.Internal, chunk.Error)
+	}
+	if chunk.HotelChunk == nil {
+		return &pb.HotelChunk{}, nil
+	}
+	return chunk.HotelChunk, nil
+}
+
+// readProtoChunk decodes the next message of a length-delimited protobuf
+// stream. It reads the whole message before decoding it, so the trace
+// times the decoding alone.
+func readProtoChunk(reader *bufio.Reader, trace *chunkTrace) (*pb.HotelChunk, error) {
+	size, err := binary.ReadUvarint(reader)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, truncatedChunkError(err)
+	}
+	if size > maxChunkSize {
+		return nil, status.Errorf(codes.ResourceExhausted, "chunk of %d bytes exceeds the %d bytes limit", size, maxChunkSize)
+	}
+
+	message := make([]byte, size)
+	if _, err := io.ReadFull(reader, message); err != nil {
+		return nil, truncatedChunkError(err)
+	}
+
+	chunk := &pb.HotelChunk{}
+	start := time.Now()
+	err = proto.Unmarshal(message, chunk)
+	trace.decoded(len(message), time.Since(start))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "decode chunk: %v", err)
+	}
+	return chunk, nil
+}
+
+// truncatedChunkError reports a protobuf chunk that could not be read whole
+func truncatedChunkError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return status.Error(codes.Unavailable, "stream ended in the middle of a chunk")
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// httpError converts an error response of the HTTP microservice to a gRPC
+// status error, keeping its {"error": ...} message when there is one
+func httpError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	if message == "" {
+		message = resp.Status
+	}
+	return status.Error(grpcCodeFromHTTP(resp.StatusCode), message)
+}
+
+// grpcCodeFromHTTP is the inverse of httpStatusFromGRPC
+func grpcCodeFromHTTP(code int) codes.Code {
+	switch code {
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	default:
+		return codes.Internal
+	}
+}
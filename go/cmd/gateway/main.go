@@ -2,106 +2,285 @@ package main
 
 import (
 	"context"
-	"io"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/codec"
+	"grpc-vs-http/internal/compression"
+	"grpc-vs-http/internal/latency"
+	"grpc-vs-http/internal/metrics"
+	"grpc-vs-http/internal/tracing"
 	pb "grpc-vs-http/proto"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
 // StatsResponse represents the response from the gateway
 type StatsResponse struct {
-	ProcessTimeMs   int64 `json:"processTimeMs"`
-	TotalHotels     int   `json:"totalHotels"`
-	AvailableHotels int   `json:"availableHotels"`
+	ProcessTimeMs   int64         `json:"processTimeMs"`
+	TotalHotels     int           `json:"totalHotels"`
+	AvailableHotels int           `json:"availableHotels"`
+	ReceivedHotels  int           `json:"receivedHotels,omitempty"` // Hotels sent by the microservice, when filtering in the gateway
+	FilterMode      string        `json:"filterMode,omitempty"`
+	Detail          *StreamDetail `json:"detail,omitempty"` // Only with /stats?detail=true
+
+	// Only with ?compression=: received gRPC messages, as sent and decompressed
+	Compression       string `json:"compression,omitempty"`
+	CompressedBytes   int64  `json:"compressedBytes,omitempty"`
+	UncompressedBytes int64  `json:"uncompressedBytes,omitempty"`
+
+	// Only with ?codec=: received chunks as encoded by the codec, and the time spent decoding them
+	Codec         string  `json:"codec,omitempty"`
+	EncodedBytes  int64   `json:"encodedBytes,omitempty"`
+	BytesPerHotel float64 `json:"bytesPerHotel,omitempty"`
+	DecodeTimeUs  int64   `json:"decodeTimeUs,omitempty"`
+
+	elapsed       time.Duration // ProcessTimeMs at full resolution
+	receivedBytes int64         // Bytes received on the wire, 0 for inproc
 }
 
 // ConcurrentStatsResponse represents the response from concurrent stats testing
 type ConcurrentStatsResponse struct {
-	TotalTimeMs     int64           `json:"totalTimeMs"`
-	ConcurrentCalls int             `json:"concurrentCalls"`
-	SuccessfulCalls int             `json:"successfulCalls"`
-	FailedCalls     int             `json:"failedCalls"`
-	AverageTimeMs   float64         `json:"averageTimeMs"`
-	MinTimeMs       int64           `json:"minTimeMs"`
-	MaxTimeMs       int64           `json:"maxTimeMs"`
-	Results         []StatsResponse `json:"results"`
+	TotalTimeMs     int64            `json:"totalTimeMs"`
+	ConcurrentCalls int              `json:"concurrentCalls"`
+	SuccessfulCalls int              `json:"successfulCalls"`
+	FailedCalls     int              `json:"failedCalls"`
+	AverageTimeMs   float64          `json:"averageTimeMs"`
+	MinTimeMs       int64            `json:"minTimeMs"`
+	MaxTimeMs       int64            `json:"maxTimeMs"`
+	Latency         *latency.Summary `json:"latency,omitempty"` // Successful calls, microsecond resolution
+	Throughput      Throughput       `json:"throughput"`
+	Errors          []CallError      `json:"errors,omitempty"` // Failed calls, most frequent first
+	Results         []StatsResponse  `json:"results"`
+}
+
+// CallError groups the failed calls with the same status code, message and
+// phase. Times are offsets from the start of the run.
+type CallError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Phase     string `json:"phase"` // dial, open or recv
+	Count     int    `json:"count"`
+	FirstAtMs int64  `json:"firstAtMs"`
+	LastAtMs  int64  `json:"lastAtMs"`
+}
+
+// streamFailure is the error of a failed streamStats call. It keeps the
+// gRPC status of the source's error.
+type streamFailure struct {
+	err   error
+	phase string
+	at    time.Time
+}
+
+func (f *streamFailure) Error() string {
+	return f.err.Error()
+}
+
+func (f *streamFailure) GRPCStatus() *status.Status {
+	return status.Convert(f.err)
+}
+
+// Throughput is measured over the wall-clock time of all concurrent calls
+type Throughput struct {
+	CallsPerSec  float64 `json:"callsPerSec"`
+	HotelsPerSec float64 `json:"hotelsPerSec"`
+	BytesPerSec  float64 `json:"bytesPerSec"` // Bytes received on the wire, in the transport's own format
+}
+
+// AggregateStatsResponse represents the response of the aggregation endpoint
+type AggregateStatsResponse struct {
+	ProcessTimeMs int64          `json:"processTimeMs"`
+	Stats         *pb.HotelStats `json:"stats"`
 }
 
 // GatewayServer handles HTTP requests and calls gRPC microservice
 type GatewayServer struct {
-	client pb.DataServiceClient
+	client  pb.DataServiceClient
+	sources map[string]HotelSource // Keyed by ?transport= value
+	metrics *metrics.Metrics
 }
 
-// NewGatewayServer creates a new gateway server
-func NewGatewayServer(client pb.DataServiceClient) *GatewayServer {
-	return &GatewayServer{client: client}
+// NewGatewayServer creates a new gateway server. The streaming endpoints
+// read hotels from sources, the unary ones call client directly. Every
+// source is counted into m.
+func NewGatewayServer(client pb.DataServiceClient, sources map[string]HotelSource, m *metrics.Metrics) *GatewayServer {
+	metered := make(map[string]HotelSource, len(sources))
+	for transport, source := range sources {
+		metered[transport] = meteredSource{transport: transport, source: source, metrics: m}
+	}
+	return &GatewayServer{client: client, sources: metered, metrics: m}
 }
 
-// handleStats processes the /stats endpoint using streaming
-func (g *GatewayServer) handleStats(c *gin.Context) {
+// streamStats reads one stream from source and counts the hotels it
+// returns. A non-nil localFilter is applied to the received hotels, to
+// compare against filtering in the microservice. With detail, the stream is
+// traced and broken down in stats.Detail; with a compressor set on ctx, the
+// compressed and uncompressed bytes are reported, with a codec its encoded
+// size and decode time. The bytes received on the wire are counted by the
+// transport as it reads them, at no cost per chunk. Errors are *streamFailure.
+func streamStats(ctx context.Context, source HotelSource, req *pb.StreamRequest, localFilter *pb.HotelFilter, detail bool) (StatsResponse, error) {
 	startTime := time.Now()
+	ctx, wire := metrics.WithWireCounter(ctx)
+
+	compressor := compressionFrom(ctx)
+	codecName := codecFrom(ctx)
+	var trace *chunkTrace
+	if detail || compressor != "" || codecName != "" {
+		trace = &chunkTrace{}
+		ctx = withChunkTrace(ctx, trace)
+	}
+	var recorder *detailRecorder
+	if detail {
+		recorder = newDetailRecorder(startTime, trace)
+	}
+
+	var stats StatsResponse
+	var receivedHotels, chunks int
+
+	// Receive all chunks and process them
+	err := source.Stream(ctx, req, func(chunk *pb.HotelChunk) error {
+		chunks++
+		if recorder != nil {
+			recorder.chunk(chunk)
+		}
+		receivedHotels += stats.count(chunk.Hotels, localFilter)
+		return nil
+	})
+	stats.receivedBytes = wire.Bytes()
+	if err != nil {
+		return StatsResponse{}, &streamFailure{err: err, phase: failurePhase(err, chunks), at: time.Now()}
+	}
 
-	// Get chunk size from query parameter, default to 100
-	chunkSize := int32(100)
-	if chunkParam := c.Query("chunkSize"); chunkParam != "" {
-		if parsed, err := strconv.ParseInt(chunkParam, 10, 32); err == nil && parsed > 0 {
-			chunkSize = int32(parsed)
+	stats.finish(startTime, req, localFilter, receivedHotels)
+	if recorder != nil {
+		stats.Detail = recorder.finish()
+	}
+	if compressor != "" {
+		stats.Compression = compressor
+		stats.CompressedBytes = trace.compressedBytes
+		stats.UncompressedBytes = trace.uncompressedBytes
+	}
+	if codecName != "" {
+		stats.Codec = codecName
+		stats.EncodedBytes = trace.wireBytes
+		stats.DecodeTimeUs = trace.decode.Microseconds()
+		if receivedHotels > 0 {
+			stats.BytesPerHotel = float64(trace.wireBytes) / float64(receivedHotels)
 		}
 	}
+	return stats, nil
+}
 
-	log.Printf("Processing stats with chunk size: %d", chunkSize)
+// annotateSpan records the transport and chunk size of a stream request on
+// the span of ctx, the request's when tracing is enabled
+func annotateSpan(ctx context.Context, transport string, req *pb.StreamRequest) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("hotels.transport", transport),
+		attribute.Int("hotels.chunk_size", int(req.GetChunkSize())),
+	)
+}
 
-	// Call gRPC microservice using streaming
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// count adds the hotels of one chunk to the totals and returns how many
+// were received. A non-nil localFilter skips the hotels it does not match.
+func (stats *StatsResponse) count(hotels []*pb.Hotel, localFilter *pb.HotelFilter) int {
+	for _, hotel := range hotels {
+		if localFilter != nil && !catalog.Match(hotel, localFilter) {
+			continue
+		}
+		stats.TotalHotels++
+		if hotel.Available != nil && *hotel.Available {
+			stats.AvailableHotels++
+		}
+	}
+	return len(hotels)
+}
 
-	stream, err := g.client.GetHotelsStreaming(ctx, &pb.StreamRequest{ChunkSize: chunkSize})
+// finish records where the stream was filtered and how long it took
+func (stats *StatsResponse) finish(startTime time.Time, req *pb.StreamRequest, localFilter *pb.HotelFilter, receivedHotels int) {
+	if localFilter != nil {
+		stats.ReceivedHotels = receivedHotels
+		stats.FilterMode = "gateway"
+	} else if req.Filter != nil {
+		stats.FilterMode = "server"
+	}
+	stats.elapsed = time.Since(startTime)
+	stats.ProcessTimeMs = stats.elapsed.Milliseconds()
+}
+
+// handleStats processes the /stats endpoint using streaming
+func (g *GatewayServer) handleStats(c *gin.Context) {
+	req, localFilter, err := parseStreamRequest(c)
 	if err != nil {
-		log.Printf("gRPC streaming call failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data from microservice"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	countAvailable(req)
+
+	transport, source, err := g.parseTransport(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var totalHotels int
-	var availableHotels int
+	detail, err := strconv.ParseBool(c.DefaultQuery("detail", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("detail: %q is not a boolean", c.Query("detail"))})
+		return
+	}
 
-	// Receive all chunks and process them
-	for {
-		chunk, err := stream.Recv()
-		if err != nil {
-			// if err.Error() == "EOF" {
-			if err == io.EOF {
-				break
-			}
-			log.Printf("gRPC stream receive failed: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to receive data from microservice"})
-			return
-		}
+	compressor, err := parseCompression(c, transport)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-		// Count hotels in this chunk
-		totalHotels += len(chunk.Hotels)
-		for _, hotel := range chunk.Hotels {
-			if hotel.Available != nil && *hotel.Available {
-				availableHotels++
-			}
-		}
+	codecName, err := parseCodec(c, transport)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	processTime := time.Since(startTime).Milliseconds()
+	log.Printf("Processing stats with chunk size: %d over %s", req.ChunkSize, transport)
 
-	stats := StatsResponse{
-		ProcessTimeMs:   processTime,
-		TotalHotels:     totalHotels,
-		AvailableHotels: availableHotels,
+	// Call the microservice using streaming. The call outlives a client that
+	// disconnects, but belongs to the request's trace.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 30*time.Second)
+	defer cancel()
+	annotateSpan(ctx, transport, req)
+	if compressor != "" {
+		ctx = withCompression(ctx, compressor)
+	}
+	if codecName != "" {
+		ctx = withCodec(ctx, codecName)
+	}
+
+	stats, err := streamStats(ctx, source, req, localFilter, detail)
+	if err != nil {
+		log.Printf("%s streaming call failed: %v", transport, err)
+		if status.Code(err) == codes.InvalidArgument {
+			c.JSON(http.StatusBadRequest, gin.H{"error": status.Convert(err).Message()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data from microservice"})
+		return
 	}
 
 	c.JSON(http.StatusOK, stats)
@@ -119,19 +298,44 @@ func (g *GatewayServer) handleConcurrentStats(c *gin.Context) {
 		}
 	}
 
-	// Get chunk size from query parameter, default to 100
-	chunkSize := int32(100)
-	if chunkParam := c.Query("chunkSize"); chunkParam != "" {
-		if parsed, err := strconv.ParseInt(chunkParam, 10, 32); err == nil && parsed > 0 {
-			chunkSize = int32(parsed)
-		}
+	req, localFilter, err := parseStreamRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	countAvailable(req)
+
+	transport, source, err := g.parseTransport(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	log.Printf("Processing %d concurrent stats calls with chunk size: %d", concurrentCalls, chunkSize)
+	compressor, err := parseCompression(c, transport)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codecName, err := parseCodec(c, transport)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "hgrm" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of json, hgrm"})
+		return
+	}
+
+	log.Printf("Processing %d concurrent stats calls with chunk size: %d over %s", concurrentCalls, req.ChunkSize, transport)
+	annotateSpan(c.Request.Context(), transport, req)
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.Int("hotels.calls", concurrentCalls))
 
 	// Create channels for collecting results
 	resultsChan := make(chan StatsResponse, concurrentCalls)
-	errorsChan := make(chan error, concurrentCalls)
+	errorsChan := make(chan *streamFailure, concurrentCalls)
 
 	// Launch concurrent goroutines
 	var wg sync.WaitGroup
@@ -140,47 +344,20 @@ func (g *GatewayServer) handleConcurrentStats(c *gin.Context) {
 		go func() {
 			defer wg.Done()
 
-			// Call gRPC microservice using streaming
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			// Call the microservice using streaming
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 30*time.Second)
 			defer cancel()
-
-			callStartTime := time.Now()
-
-			stream, err := g.client.GetHotelsStreaming(ctx, &pb.StreamRequest{ChunkSize: chunkSize})
-			if err != nil {
-				errorsChan <- err
-				return
+			if compressor != "" {
+				ctx = withCompression(ctx, compressor)
 			}
-
-			var totalHotels int
-			var availableHotels int
-
-			// Receive all chunks and process them
-			for {
-				chunk, err := stream.Recv()
-				if err != nil {
-					if err == io.EOF {
-						break
-					}
-					errorsChan <- err
-					return
-				}
-
-				// Count hotels in this chunk
-				totalHotels += len(chunk.Hotels)
-				for _, hotel := range chunk.Hotels {
-					if hotel.Available != nil && *hotel.Available {
-						availableHotels++
-					}
-				}
+			if codecName != "" {
+				ctx = withCodec(ctx, codecName)
 			}
 
-			callProcessTime := time.Since(callStartTime).Milliseconds()
-
-			result := StatsResponse{
-				ProcessTimeMs:   callProcessTime,
-				TotalHotels:     totalHotels,
-				AvailableHotels: availableHotels,
+			result, err := streamStats(ctx, source, req, localFilter, false)
+			if err != nil {
+				errorsChan <- err.(*streamFailure)
+				return
 			}
 
 			resultsChan <- result
@@ -194,7 +371,7 @@ func (g *GatewayServer) handleConcurrentStats(c *gin.Context) {
 
 	// Collect results
 	var results []StatsResponse
-	var errors []error
+	var errors []*streamFailure
 
 	for result := range resultsChan {
 		results = append(results, result)
@@ -204,21 +381,34 @@ func (g *GatewayServer) handleConcurrentStats(c *gin.Context) {
 		errors = append(errors, err)
 	}
 
-	totalTime := time.Since(startTime).Milliseconds()
+	totalTime := time.Since(startTime)
 
 	// Calculate statistics
-	var minTime int64 = 999999
-	var maxTime int64 = 0
-	var totalProcessTime int64 = 0
+	histogram := latency.NewHistogram()
+	var minTime, maxTime, totalProcessTime int64
+	var totalHotels, totalBytes int64
 
-	for _, result := range results {
-		if result.ProcessTimeMs < minTime {
+	for i, result := range results {
+		if i == 0 || result.ProcessTimeMs < minTime {
 			minTime = result.ProcessTimeMs
 		}
 		if result.ProcessTimeMs > maxTime {
 			maxTime = result.ProcessTimeMs
 		}
 		totalProcessTime += result.ProcessTimeMs
+		histogram.Record(result.elapsed)
+		totalHotels += int64(result.TotalHotels)
+		totalBytes += result.receivedBytes
+	}
+
+	if format == "hgrm" {
+		c.Header("Content-Disposition", `attachment; filename="concurrent-stats.hgrm"`)
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := histogram.WritePercentiles(c.Writer); err != nil {
+			log.Printf("Failed to write histogram: %v", err)
+		}
+		return
 	}
 
 	averageTime := float64(0)
@@ -226,30 +416,199 @@ func (g *GatewayServer) handleConcurrentStats(c *gin.Context) {
 		averageTime = float64(totalProcessTime) / float64(len(results))
 	}
 
+	seconds := totalTime.Seconds()
 	response := ConcurrentStatsResponse{
-		TotalTimeMs:     totalTime,
+		TotalTimeMs:     totalTime.Milliseconds(),
 		ConcurrentCalls: concurrentCalls,
 		SuccessfulCalls: len(results),
 		FailedCalls:     len(errors),
+		Errors:          groupErrors(errors, startTime),
 		AverageTimeMs:   averageTime,
 		MinTimeMs:       minTime,
 		MaxTimeMs:       maxTime,
-		Results:         results,
+		Latency:         histogram.Summary(),
+		Throughput: Throughput{
+			CallsPerSec:  float64(len(results)) / seconds,
+			HotelsPerSec: float64(totalHotels) / seconds,
+			BytesPerSec:  float64(totalBytes) / seconds,
+		},
+		Results: results,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// setupRoutes configures the HTTP routes
-func (g *GatewayServer) setupRoutes() *gin.Engine {
+// groupErrors counts failures by status code, message and phase
+func groupErrors(failures []*streamFailure, startTime time.Time) []CallError {
+	var groups []CallError
+	index := make(map[CallError]int) // Key has only Code, Message and Phase set
+	for _, failure := range failures {
+		st := status.Convert(failure.err)
+		key := CallError{Code: st.Code().String(), Message: st.Message(), Phase: failure.phase}
+		at := failure.at.Sub(startTime).Milliseconds()
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			key.FirstAtMs, key.LastAtMs = at, at
+			groups = append(groups, key)
+		}
+		group := &groups[i]
+		group.Count++
+		group.FirstAtMs = min(group.FirstAtMs, at)
+		group.LastAtMs = max(group.LastAtMs, at)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].FirstAtMs < groups[j].FirstAtMs
+	})
+	return groups
+}
+
+// handleAggregateStats processes the /stats/aggregate endpoint, letting the
+// microservice count the hotels instead of shipping them to the gateway
+func (g *GatewayServer) handleAggregateStats(c *gin.Context) {
+	startTime := time.Now()
+
+	req, err := parseHotelStatsRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	stats, err := g.client.GetHotelStats(ctx, req)
+	if err != nil {
+		log.Printf("gRPC GetHotelStats call failed: %v", err)
+		c.JSON(httpStatusFromGRPC(err), gin.H{"error": status.Convert(err).Message()})
+		return
+	}
+
+	c.JSON(http.StatusOK, AggregateStatsResponse{
+		ProcessTimeMs: time.Since(startTime).Milliseconds(),
+		Stats:         stats,
+	})
+}
+
+// handleListHotels processes the /hotels endpoint, returning one page of
+// hotels per unary ListHotels call
+func (g *GatewayServer) handleListHotels(c *gin.Context) {
+	req, err := parseListHotelsRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, err := g.client.ListHotels(ctx, req)
+	if err != nil {
+		log.Printf("gRPC ListHotels call failed: %v", err)
+		c.JSON(httpStatusFromGRPC(err), gin.H{"error": status.Convert(err).Message()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// handleGetHotel processes the /hotels/:id endpoint with a unary lookup.
+// The id is a hotelId unless ?by=giataId or ?by=hUid is given.
+func (g *GatewayServer) handleGetHotel(c *gin.Context) {
+	id := c.Param("id")
+
+	req := &pb.GetHotelRequest{}
+	switch by := c.DefaultQuery("by", "hotelId"); by {
+	case "hotelId":
+		req.Key = &pb.GetHotelRequest_HotelId{HotelId: id}
+	case "giataId", "hUid":
+		parsed, err := strconv.ParseInt(id, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": by + " must be an integer"})
+			return
+		}
+		if by == "giataId" {
+			req.Key = &pb.GetHotelRequest_GiataId{GiataId: int32(parsed)}
+		} else {
+			req.Key = &pb.GetHotelRequest_HUid{HUid: int32(parsed)}
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "by must be one of hotelId, giataId, hUid"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	hotel, err := g.client.GetHotel(ctx, req)
+	if err != nil {
+		log.Printf("gRPC GetHotel call failed: %v", err)
+		c.JSON(httpStatusFromGRPC(err), gin.H{"error": status.Convert(err).Message()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hotel)
+}
+
+// httpStatusFromGRPC maps a gRPC error to the closest HTTP status code
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// setupRoutes registers the endpoints, behind the given middleware
+func (g *GatewayServer) setupRoutes(middleware ...gin.HandlerFunc) *gin.Engine {
 	r := gin.Default()
+	r.Use(middleware...)
+	r.Use(g.metrics.Gin())
 
 	// Streaming endpoint
 	r.GET("/stats", g.handleStats)
 
+	// Aggregation computed by the microservice
+	r.GET("/stats/aggregate", g.handleAggregateStats)
+
 	// Concurrent stats endpoint
 	r.GET("/concurrent-stats", g.handleConcurrentStats)
 
+	// Chunks forwarded to the client as they arrive
+	r.GET("/stream/ndjson", g.handleStreamNDJSON)
+
+	// Live progress of a stream as Server-Sent Events
+	r.GET("/stream/sse", g.handleStreamSSE)
+
+	// WebSocket bridge, several subscriptions per socket
+	r.GET("/stream/ws", g.handleStreamWebSocket)
+
+	// Paginated unary listing
+	r.GET("/hotels", g.handleListHotels)
+
+	// Single hotel lookup
+	r.GET("/hotels/:id", g.handleGetHotel)
+
+	// Prometheus metrics
+	r.GET("/metrics", gin.WrapH(g.metrics.Handler()))
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
@@ -259,6 +618,36 @@ func (g *GatewayServer) setupRoutes() *gin.Engine {
 }
 
 func main() {
+	microserviceAddr := flag.String("microservice", "localhost:50051", "address of the gRPC microservice")
+	httpServiceURL := flag.String("httpservice", "http://localhost:3002", "base URL of the HTTP microservice, for transport=http-json|http-proto")
+	connectURL := flag.String("connect", "http://localhost:50052", "base URL of the microservice's Connect listener, for transport=connect")
+	var dataPaths catalog.PathList
+	flag.Var(&dataPaths, "data", "load a data file or directory into the gateway, for transport=inproc (repeat or comma-separate to merge several)")
+	traceExporter := flag.String("trace", "", "export OpenTelemetry spans to otlp (OTEL_EXPORTER_OTLP_* variables), otlp://host:port, stdout or a file path (empty disables)")
+	flag.Parse()
+
+	if *traceExporter != "" {
+		shutdown, err := tracing.Setup("gateway", *traceExporter)
+		if err != nil {
+			log.Fatalf("Failed to set up tracing: %v", err)
+		}
+		log.Printf("Tracing to %s", *traceExporter)
+		go tracing.FlushOnSignal(shutdown)
+	}
+
+	// The in-process source only loads data when asked to, it doubles the memory footprint
+	var inproc *catalog.Catalog
+	if len(dataPaths) > 0 {
+		source, err := catalog.Resolve(dataPaths, "")
+		if err != nil {
+			log.Fatalf("Failed to load data: %v", err)
+		}
+		if inproc, err = source.Load(); err != nil {
+			log.Fatalf("Failed to load data: %v", err)
+		}
+		log.Printf("Loaded %d hotels from %s for transport=inproc", len(inproc.Hotels), source)
+	}
+
 	// Connect to gRPC microservice with optimized settings
 	kacp := keepalive.ClientParameters{
 		Time:                2 * time.Minute,
@@ -266,31 +655,68 @@ func main() {
 		PermitWithoutStream: true,
 	}
 
+	m := metrics.New()
+	grpcMetrics := m.GRPCClient()
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(grpcMetrics.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(grpcMetrics.StreamClientInterceptor()),
 		grpc.WithKeepaliveParams(kacp),
+		grpc.WithStatsHandler(traceStatsHandler{}),       // Compressed sizes for ?compression=
+		grpc.WithStatsHandler(metrics.ClientWireStats{}), // Received bytes, for throughput
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(1000*1024*1024), // 100MB
 			grpc.MaxCallSendMsgSize(1000*1024*1024), // 100MB
 		),
 	}
+	if *traceExporter != "" {
+		// A span per call, with an event per received message, whose
+		// context is sent to the microservice in the call's metadata
+		opts = append(opts, grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents))))
+	}
 
-	conn, err := grpc.Dial("localhost:50051", opts...)
+	conn, err := grpc.Dial(*microserviceAddr, opts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to gRPC server: %v", err)
 	}
 	defer conn.Close()
 
 	client := pb.NewDataServiceClient(conn)
-	gateway := NewGatewayServer(client)
+	httpClient := newHTTPClient()
+	baseURL := strings.TrimSuffix(*httpServiceURL, "/")
+	gateway := NewGatewayServer(client, map[string]HotelSource{
+		transportGRPC:      grpcStreamSource{conn: conn, client: client},
+		transportGRPCUnary: grpcUnarySource{conn: conn, client: client},
+		transportHTTPJSON:  httpSource{client: httpClient, baseURL: baseURL},
+		transportHTTPProto: httpSource{client: httpClient, baseURL: baseURL, proto: true},
+		transportConnect:   newConnectSource(httpClient, strings.TrimSuffix(*connectURL, "/")),
+		transportInProc:    inprocSource{catalog: inproc},
+	}, m)
 
 	// Setup routes
-	router := gateway.setupRoutes()
+	var middleware []gin.HandlerFunc
+	if *traceExporter != "" {
+		middleware = append(middleware, otelgin.Middleware("gateway"))
+	}
+	router := gateway.setupRoutes(middleware...)
 
 	log.Println("Gateway running on port 8080")
 	log.Println("Endpoints:")
 	log.Println("  - GET /stats?chunkSize=<size> (hotel statistics with configurable chunk size, default: 100)")
-	log.Println("  - GET /concurrent-stats?calls=<num>&chunkSize=<size> (concurrent hotel statistics, default: 10 calls)")
+	log.Println("      filters: country, cityId, minRating, maxRating, minPrice, maxPrice, available, board, tag; filterMode=server|gateway")
+	log.Println("      projection: fields=<path>,... (e.g. fields=available,rooms.rates.amount)")
+	log.Println("      detail=true (time to first chunk, chunk gaps, wire/decoded bytes, decode time)")
+	log.Println("      compression=" + strings.Join(compression.Names, "|") + " (grpc and grpc-unary transports, reports compressed/uncompressed bytes)")
+	log.Println("      codec=" + strings.Join(codec.Names, "|") + " (grpc and grpc-unary transports, reports encoded bytes per hotel and decode time)")
+	log.Println("      transport=" + strings.Join(transports, "|") + " (default: grpc; http-* use " + *httpServiceURL + ", connect uses " + *connectURL + ", inproc needs --data)")
+	log.Println("  - GET /stats/aggregate?groupBy=country,city,stars (counts computed by the microservice, same filters)")
+	log.Println("  - GET /concurrent-stats?calls=<num>&chunkSize=<size> (concurrent hotel statistics, default: 10 calls, same filters/transport; format=hgrm for the latency histogram)")
+	log.Println("  - GET /stream/ndjson?chunkSize=<size> (every chunk forwarded as one JSON line, same filters/fields/transport)")
+	log.Println("  - GET /stream/sse?chunkSize=<size> (per-chunk progress events and a final summary, same filters/fields/transport)")
+	log.Println("  - GET /stream/ws (WebSocket: subscribe/cancel messages, JSON or binary protobuf chunk frames)")
+	log.Println("  - GET /hotels?pageSize=<size>&pageToken=<token> (paginated unary listing, default: 100 per page)")
+	log.Println("  - GET /hotels/:id?by=hotelId|giataId|hUid (single hotel lookup, default: hotelId)")
+	log.Println("  - GET /metrics (Prometheus: gRPC client, HTTP routes, hotel streams per transport, Go runtime)")
 	log.Println("  - GET /health (health check)")
 
 	if err := router.Run(":8080"); err != nil {
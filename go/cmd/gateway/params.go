@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"grpc-vs-http/internal/codec"
+	"grpc-vs-http/internal/compression"
+	"grpc-vs-http/internal/hotelquery"
+	pb "grpc-vs-http/proto"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// parseStreamRequest builds a StreamRequest from the chunk size, filter and
+// fields query parameters. With ?filterMode=gateway the filter is returned
+// separately, to be applied by the gateway instead of the microservice.
+func parseStreamRequest(c *gin.Context) (req *pb.StreamRequest, localFilter *pb.HotelFilter, err error) {
+	req, err = hotelquery.StreamRequest(c.Request.URL.Query())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch mode := c.DefaultQuery("filterMode", "server"); mode {
+	case "server":
+	case "gateway":
+		localFilter, req.Filter = req.Filter, nil
+	default:
+		return nil, nil, fmt.Errorf("filterMode: %q is not one of server, gateway", mode)
+	}
+
+	// The gateway filter needs the fields it tests, which a mask may have pruned
+	if localFilter != nil && req.Fields != nil {
+		return nil, nil, fmt.Errorf("fields cannot be combined with filterMode=gateway")
+	}
+	return req, localFilter, nil
+}
+
+// countAvailable adds "available" to the field mask of req when the mask
+// leaves it out, for the endpoints that count available hotels. They return
+// counts rather than hotels, so the extra field never reaches the client.
+func countAvailable(req *pb.StreamRequest) {
+	paths := req.GetFields().GetPaths()
+	if len(paths) == 0 || slices.Contains(paths, "available") {
+		return
+	}
+	req.Fields = &fieldmaskpb.FieldMask{Paths: append(slices.Clip(paths), "available")}
+}
+
+// parseTransport reads ?transport=, selecting the HotelSource the streaming
+// endpoints read hotels from
+func (g *GatewayServer) parseTransport(c *gin.Context) (string, HotelSource, error) {
+	transport := c.DefaultQuery("transport", transportGRPC)
+	source, err := g.source(transport)
+	return transport, source, err
+}
+
+// source returns the HotelSource registered for a transport name
+func (g *GatewayServer) source(transport string) (HotelSource, error) {
+	source, ok := g.sources[transport]
+	if !ok {
+		return nil, fmt.Errorf("transport: %q is not one of %s", transport, strings.Join(transports, ", "))
+	}
+	return source, nil
+}
+
+// parseCompression reads ?compression=, the compressor the gRPC sources
+// use. It returns "" when the parameter is absent.
+func parseCompression(c *gin.Context, transport string) (string, error) {
+	if !c.Request.URL.Query().Has("compression") {
+		return "", nil
+	}
+	name, err := compression.Parse(c.Query("compression"))
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(grpcTransports, transport) {
+		return "", fmt.Errorf("compression: only supported by the %s transports", strings.Join(grpcTransports, " and "))
+	}
+	return name, nil
+}
+
+// parseCodec reads ?codec=, the codec the gRPC sources use. It returns ""
+// when the parameter is absent.
+func parseCodec(c *gin.Context, transport string) (string, error) {
+	if !c.Request.URL.Query().Has("codec") {
+		return "", nil
+	}
+	name, err := codec.Parse(c.Query("codec"))
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(grpcTransports, transport) {
+		return "", fmt.Errorf("codec: only supported by the %s transports", strings.Join(grpcTransports, " and "))
+	}
+	return name, nil
+}
+
+// parseListHotelsRequest builds a ListHotelsRequest from the pagination,
+// filter and fields query parameters
+func parseListHotelsRequest(c *gin.Context) (*pb.ListHotelsRequest, error) {
+	return hotelquery.ListHotelsRequest(c.Request.URL.Query())
+}
+
+// statsGroups maps ?groupBy= values to aggregation dimensions
+var statsGroups = map[string]pb.StatsGroup{
+	"country": pb.StatsGroup_STATS_GROUP_COUNTRY,
+	"city":    pb.StatsGroup_STATS_GROUP_CITY,
+	"stars":   pb.StatsGroup_STATS_GROUP_STARS,
+}
+
+// parseHotelStatsRequest builds a HotelStatsRequest from the filter and
+// groupBy query parameters
+func parseHotelStatsRequest(c *gin.Context) (*pb.HotelStatsRequest, error) {
+	values := c.Request.URL.Query()
+	filter, err := hotelquery.Filter(values)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.HotelStatsRequest{Filter: filter}
+	for _, value := range hotelquery.List(values, "groupBy") {
+		group, ok := statsGroups[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("groupBy: %q is not one of country, city, stars", value)
+		}
+		req.GroupBy = append(req.GroupBy, group)
+	}
+	return req, nil
+}
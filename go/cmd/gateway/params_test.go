@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestCountAvailable(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{name: "no mask"},
+		{name: "mask without available", paths: []string{"hotelId", "rooms.rates.amount"}, want: []string{"hotelId", "rooms.rates.amount", "available"}},
+		{name: "mask with available", paths: []string{"available", "hotelId"}, want: []string{"available", "hotelId"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pb.StreamRequest{}
+			if tt.paths != nil {
+				req.Fields = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+			countAvailable(req)
+			if got := req.GetFields().GetPaths(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
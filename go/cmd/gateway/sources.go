@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/codec"
+	"grpc-vs-http/internal/compression"
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+	"grpc-vs-http/proto/protoconnect"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// Transports a HotelSource can be selected by with ?transport=
+const (
+	transportGRPC      = "grpc"       // GetHotelsStreaming on the gRPC microservice
+	transportGRPCUnary = "grpc-unary" // One ListHotels call per chunk
+	transportHTTPJSON  = "http-json"  // GET /data/chunked on the HTTP microservice, NDJSON
+	transportHTTPProto = "http-proto" // GET /data/chunked on the HTTP microservice, delimited protobuf
+	transportConnect   = "connect"    // GetHotelsStreaming over the Connect protocol, protobuf on plain HTTP
+	transportInProc    = "inproc"     // The gateway's own copy of the catalog, no network
+)
+
+// transports lists the valid ?transport= values, default first
+var transports = []string{transportGRPC, transportGRPCUnary, transportHTTPJSON, transportHTTPProto, transportConnect, transportInProc}
+
+// grpcTransports lists the transports that honor ?compression= and ?codec=
+var grpcTransports = []string{transportGRPC, transportGRPCUnary}
+
+// HotelSource delivers the chunks of one hotel stream. The streaming
+// handlers depend on it rather than on a client, so every transport is
+// measured through the same code path.
+type HotelSource interface {
+	// Stream calls fn with every chunk matching req, in order. It stops at
+	// the first error returned by fn and returns it unchanged; failures of
+	// the source itself are gRPC status errors whatever the transport.
+	Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error
+}
+
+// Phases a stream can fail in, as reported by /concurrent-stats
+const (
+	phaseDial = "dial" // No connection to the microservice could be made
+	phaseOpen = "open" // The stream failed before its first chunk
+	phaseRecv = "recv" // The stream failed after delivering chunks
+)
+
+// dialError is a source failure caused by the connection to the
+// microservice rather than by the call. It keeps the gRPC status of the
+// error, so status.Code and status.Convert see it unchanged.
+type dialError struct {
+	st *status.Status
+}
+
+func (e dialError) Error() string {
+	return e.st.Err().Error()
+}
+
+func (e dialError) GRPCStatus() *status.Status {
+	return e.st
+}
+
+// failurePhase tells which phase of a stream err ended, given how many
+// chunks had been delivered before it
+func failurePhase(err error, chunks int) string {
+	switch {
+	case chunks > 0:
+		return phaseRecv
+	case errors.As(err, &dialError{}):
+		return phaseDial
+	default:
+		return phaseOpen
+	}
+}
+
+// isDial reports whether err, returned by a net/http client, happened while
+// connecting
+func isDial(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// grpcCallError marks an Unavailable error as a dial failure when conn
+// could not connect. gRPC dials lazily, so connection failures only show up
+// as failed calls.
+func grpcCallError(conn *grpc.ClientConn, err error) error {
+	if status.Code(err) == codes.Unavailable && conn.GetState() == connectivity.TransientFailure {
+		return dialError{st: status.Convert(err)}
+	}
+	return err
+}
+
+type compressionKey struct{}
+
+// withCompression makes gRPC sources compress their calls with the named
+// compressor. The microservice compresses its responses with the same one.
+func withCompression(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, compressionKey{}, name)
+}
+
+// compressionFrom returns the compressor name set on ctx, or ""
+func compressionFrom(ctx context.Context) string {
+	name, _ := ctx.Value(compressionKey{}).(string)
+	return name
+}
+
+type codecKey struct{}
+
+// withCodec makes gRPC sources encode their calls with the named codec. The
+// microservice answers with the same one.
+func withCodec(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, codecKey{}, name)
+}
+
+// codecFrom returns the codec name set on ctx, or ""
+func codecFrom(ctx context.Context) string {
+	name, _ := ctx.Value(codecKey{}).(string)
+	return name
+}
+
+// grpcCallOptions applies the compressor, the codec and the chunk trace of
+// ctx to a gRPC call
+func grpcCallOptions(ctx context.Context) []grpc.CallOption {
+	var opts []grpc.CallOption
+	if name := compressionFrom(ctx); name != "" && name != compression.None {
+		opts = append(opts, grpc.UseCompressor(name))
+	}
+	name := codecFrom(ctx)
+	if name == "" {
+		name = codec.Proto
+	}
+	if trace := chunkTraceFrom(ctx); trace != nil {
+		// ForceCodec also sets the content-subtype, from the codec's name
+		opts = append(opts, grpc.ForceCodec(traceCodec{trace: trace, codec: codec.Get(name)}))
+	} else if name != codec.Proto {
+		opts = append(opts, grpc.CallContentSubtype(name))
+	}
+	return opts
+}
+
+// grpcStreamSource reads chunks from a GetHotelsStreaming call
+type grpcStreamSource struct {
+	conn   *grpc.ClientConn
+	client pb.DataServiceClient
+}
+
+func (s grpcStreamSource) Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error {
+	stream, err := s.client.GetHotelsStreaming(ctx, req, grpcCallOptions(ctx)...)
+	if err != nil {
+		return grpcCallError(s.conn, err)
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return grpcCallError(s.conn, err)
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcUnarySource turns ListHotels pages into chunks, paying one unary
+// round trip per chunk instead of streaming. Chunks are capped at the
+// microservice's maximum page size.
+type grpcUnarySource struct {
+	conn   *grpc.ClientConn
+	client pb.DataServiceClient
+}
+
+func (s grpcUnarySource) Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error {
+	pageSize := int(req.GetChunkSize())
+	if pageSize <= 0 {
+		pageSize = catalog.DefaultChunkSize
+	}
+	pageSize = min(pageSize, catalog.MaxPageSize)
+
+	pageToken := ""
+	for chunkIndex := int32(0); ; chunkIndex++ {
+		page, err := s.client.ListHotels(ctx, &pb.ListHotelsRequest{
+			PageSize:  int32(pageSize),
+			PageToken: pageToken,
+			Filter:    req.GetFilter(),
+			Fields:    req.GetFields(),
+		}, grpcCallOptions(ctx)...)
+		if err != nil {
+			return grpcCallError(s.conn, err)
+		}
+		// Like GetHotelsStreaming, an empty result has no chunks
+		if len(page.Hotels) == 0 {
+			return nil
+		}
+
+		chunk := &pb.HotelChunk{
+			Hotels:      page.Hotels,
+			Metadata:    page.Metadata,
+			ChunkIndex:  chunkIndex,
+			TotalChunks: (page.TotalSize + int32(pageSize) - 1) / int32(pageSize), // Ceiling division
+			IsLast:      page.NextPageToken == "",
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		if chunk.IsLast {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// connectSource reads chunks from GetHotelsStreaming served by the
+// microservice's Connect listener: the same protobuf messages as grpc,
+// without gRPC's HTTP/2 framing
+type connectSource struct {
+	httpClient *http.Client
+	baseURL    string
+	client     protoconnect.DataServiceClient
+}
+
+func newConnectSource(httpClient *http.Client, baseURL string) connectSource {
+	s := connectSource{httpClient: httpClient, baseURL: baseURL}
+	s.client = s.newClient()
+	return s
+}
+
+// newClient creates a Connect client, reading messages up to 1000MB like
+// the gRPC client
+func (s connectSource) newClient(options ...connect.ClientOption) protoconnect.DataServiceClient {
+	options = append(options, connect.WithReadMaxBytes(1000*1024*1024))
+	return protoconnect.NewDataServiceClient(s.httpClient, s.baseURL, options...)
+}
+
+func (s connectSource) Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error {
+	// Codecs are per client, so a traced call gets its own; it shares the
+	// HTTP client and its connections
+	client := s.client
+	if trace := chunkTraceFrom(ctx); trace != nil {
+		client = s.newClient(connect.WithCodec(traceCodec{trace: trace, codec: codec.Get(codec.Proto)}))
+	}
+
+	stream, err := client.GetHotelsStreaming(ctx, connect.NewRequest(req))
+	if err != nil {
+		return grpcErrorFromConnect(err)
+	}
+	defer stream.Close()
+
+	for stream.Receive() {
+		if err := fn(stream.Msg()); err != nil {
+			return err
+		}
+	}
+	return grpcErrorFromConnect(stream.Err())
+}
+
+// grpcErrorFromConnect converts a Connect error to a gRPC status error with
+// the same code and message
+func grpcErrorFromConnect(err error) error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		st := status.New(codes.Code(connectErr.Code()), connectErr.Message())
+		if isDial(err) {
+			return dialError{st: st}
+		}
+		return st.Err()
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// meteredSource counts the streams of another source into the gateway's
+// metrics, as hotels, bytes received on the wire and streams in progress.
+// Bytes come from the wire counter of the call, shared with streamStats
+// when it set one; inproc receives none.
+type meteredSource struct {
+	transport string
+	source    HotelSource
+	metrics   *metrics.Metrics
+}
+
+func (s meteredSource) Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error {
+	metered := s.metrics.StreamStarted(s.transport)
+	defer metered.Done()
+
+	wire := metrics.WireCounterFrom(ctx)
+	if wire == nil {
+		ctx, wire = metrics.WithWireCounter(ctx)
+	}
+	counted := wire.Bytes()
+
+	err := s.source.Stream(ctx, req, func(chunk *pb.HotelChunk) error {
+		metered.Chunk(len(chunk.Hotels), int(wire.Bytes()-counted))
+		counted = wire.Bytes()
+		return fn(chunk)
+	})
+	// Bytes read after the last chunk, such as the end of the stream
+	metered.Chunk(0, int(wire.Bytes()-counted))
+	return err
+}
+
+// inprocSource chunks a catalog loaded into the gateway itself, as a
+// baseline without any network or serialization cost
+type inprocSource struct {
+	catalog *catalog.Catalog // nil unless the gateway was started with --data
+}
+
+func (s inprocSource) Stream(ctx context.Context, req *pb.StreamRequest, fn func(*pb.HotelChunk) error) error {
+	if s.catalog == nil {
+		return status.Error(codes.Unavailable, "the inproc transport needs the gateway to be started with --data")
+	}
+
+	var fnErr error
+	err := s.catalog.Stream(req, func(chunk *pb.HotelChunk) error {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		fnErr = fn(chunk)
+		return fnErr
+	})
+	switch {
+	case err == nil || err == fnErr:
+		return err
+	case errors.Is(err, catalog.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"grpc-vs-http/internal/catalog"
+	pb "grpc-vs-http/proto"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// handleStreamNDJSON processes the /stream/ndjson endpoint, forwarding every
+// HotelChunk to the client as one protojson line as soon as it arrives.
+//
+// Nothing is buffered: while a write to a slow client blocks, the next chunk
+// is not requested, gRPC flow control fills up and the microservice's Send
+// blocks in turn. When the client disconnects, the request context cancels
+// the stream.
+func (g *GatewayServer) handleStreamNDJSON(c *gin.Context) {
+	req, localFilter, err := parseStreamRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	transport, source, err := g.parseTransport(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	marshaler := protojson.MarshalOptions{}
+	chunks := 0
+	var writeErr error
+	err = source.Stream(ctx, req, func(chunk *pb.HotelChunk) error {
+		if localFilter != nil {
+			chunk.Hotels = catalog.Filter(chunk.Hotels, localFilter)
+		}
+
+		line, err := marshaler.Marshal(chunk)
+		if err != nil {
+			writeErr = fmt.Errorf("encode chunk %d: %w", chunk.ChunkIndex, err)
+			return writeErr
+		}
+		if chunks == 0 {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+		}
+		if _, err := c.Writer.Write(append(line, '\n')); err != nil {
+			writeErr = err
+			return writeErr
+		}
+		c.Writer.Flush()
+		chunks++
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		if chunks == 0 {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+		}
+	case writeErr != nil || ctx.Err() != nil:
+		log.Printf("NDJSON client went away after %d chunks: %v", chunks, err)
+	case chunks == 0:
+		// Before the first line the status code can still report the failure
+		log.Printf("%s streaming call failed: %v", transport, err)
+		c.JSON(httpStatusFromGRPC(err), gin.H{"error": status.Convert(err).Message()})
+	default:
+		// Afterwards, a final error line tells the client the stream is truncated
+		log.Printf("%s stream receive failed: %v", transport, err)
+		writeNDJSONLine(c, gin.H{"error": status.Convert(err).Message()})
+	}
+}
+
+// ChunkProgressEvent is the payload of each "chunk" Server-Sent Event
+type ChunkProgressEvent struct {
+	ChunkIndex      int32        `json:"chunkIndex"`
+	TotalChunks     int32        `json:"totalChunks"`
+	ChunkHotels     int          `json:"chunkHotels"`     // Hotels in this chunk
+	TotalHotels     int          `json:"totalHotels"`     // Running count
+	AvailableHotels int          `json:"availableHotels"` // Running count
+	ElapsedMs       int64        `json:"elapsedMs"`
+	Metadata        *pb.Metadata `json:"metadata,omitempty"` // Only on the first chunk
+}
+
+// handleStreamSSE processes the /stream/sse endpoint, reporting the progress
+// of a hotel stream as Server-Sent Events: one "chunk" event per HotelChunk,
+// then a "summary" event with the same shape as /stats, or an "error" event
+// if the stream fails.
+func (g *GatewayServer) handleStreamSSE(c *gin.Context) {
+	startTime := time.Now()
+
+	req, localFilter, err := parseStreamRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	countAvailable(req)
+	transport, source, err := g.parseTransport(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	var progress ChunkProgressEvent
+	var receivedHotels, chunks int
+	started := false
+	start := func() {
+		c.Header("Cache-Control", "no-cache")
+		c.Header("X-Accel-Buffering", "no") // Keep reverse proxies from buffering events
+		started = true
+	}
+	err = source.Stream(ctx, req, func(chunk *pb.HotelChunk) error {
+		if !started {
+			start()
+		}
+
+		receivedHotels += len(chunk.Hotels)
+		hotels := chunk.Hotels
+		if localFilter != nil {
+			hotels = catalog.Filter(hotels, localFilter)
+		}
+
+		progress.ChunkIndex = chunk.ChunkIndex
+		progress.TotalChunks = chunk.TotalChunks
+		progress.ChunkHotels = len(hotels)
+		progress.TotalHotels += len(hotels)
+		for _, hotel := range hotels {
+			if hotel.Available != nil && *hotel.Available {
+				progress.AvailableHotels++
+			}
+		}
+		progress.ElapsedMs = time.Since(startTime).Milliseconds()
+		progress.Metadata = chunk.Metadata
+
+		c.SSEvent("chunk", progress)
+		c.Writer.Flush()
+		chunks++
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Printf("SSE client went away after %d chunks", chunks)
+			return
+		}
+		// Before the first event the status code can still report the failure
+		if !started {
+			log.Printf("%s streaming call failed: %v", transport, err)
+			c.JSON(httpStatusFromGRPC(err), gin.H{"error": status.Convert(err).Message()})
+			return
+		}
+		log.Printf("%s stream receive failed: %v", transport, err)
+		c.SSEvent("error", gin.H{"error": status.Convert(err).Message()})
+		c.Writer.Flush()
+		return
+	}
+	if !started {
+		start()
+	}
+
+	summary := StatsResponse{
+		TotalHotels:     progress.TotalHotels,
+		AvailableHotels: progress.AvailableHotels,
+	}
+	summary.finish(startTime, req, localFilter, receivedHotels)
+	c.SSEvent("summary", summary)
+	c.Writer.Flush()
+}
+
+// writeNDJSONLine appends a JSON value as one line of the response
+func writeNDJSONLine(c *gin.Context, value any) {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.Writer.Write(append(line, '\n'))
+	c.Writer.Flush()
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"grpc-vs-http/internal/catalog"
+	pb "grpc-vs-http/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	maxSubscriptionsPerSocket = 32
+	wsWriteTimeout            = 30 * time.Second // Drops clients that stop reading entirely
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 64 * 1024,
+	// The gateway serves read-only benchmark data; accept front-ends on any origin
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsClientMessage is a JSON text frame sent by the client:
+//
+//	{"type":"subscribe","id":"a","chunkSize":100,"format":"proto","transport":"grpc","filter":{"countryCodes":["FR"]},"fields":["available"]}
+//	{"type":"cancel","id":"a"}
+type wsClientMessage struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	ChunkSize int32           `json:"chunkSize"`
+	Format    string          `json:"format"`    // "json" (default) or "proto"
+	Transport string          `json:"transport"` // Same values as ?transport= (default: grpc)
+	Filter    json.RawMessage `json:"filter"`    // HotelFilter in protojson form
+	Fields    []string        `json:"fields"`
+}
+
+// wsServerMessage is a JSON text frame sent to the client. Chunks of "json"
+// subscriptions arrive as {"type":"chunk","id":...,"chunk":{...}}; "proto"
+// subscriptions receive binary frames instead, laid out as
+// uvarint(len(id)) | id | HotelChunk protobuf.
+type wsServerMessage struct {
+	Type   string          `json:"type"` // "chunk", "done", "cancelled" or "error"
+	ID     string          `json:"id,omitempty"`
+	Chunk  json.RawMessage `json:"chunk,omitempty"`
+	Chunks int             `json:"chunks,omitempty"` // Chunks sent, on "done" and "cancelled"
+	Error  string          `json:"error,omitempty"`
+}
+
+// wsSession multiplexes the subscriptions of one socket. Each subscription
+// maps to its own HotelSource stream; closing the socket cancels them all.
+type wsSession struct {
+	gateway *GatewayServer
+	conn    *websocket.Conn
+	ctx     context.Context
+
+	writeMu sync.Mutex // gorilla/websocket allows a single concurrent writer
+
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// handleStreamWebSocket processes the /stream/ws endpoint
+func (g *GatewayServer) handleStreamWebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.Request.Context()))
+	session := &wsSession{
+		gateway:       g,
+		conn:          conn,
+		ctx:           ctx,
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+
+	session.readLoop()
+
+	// The socket is gone: stop every stream and wait for them to unwind
+	cancel()
+	session.wg.Wait()
+}
+
+// readLoop dispatches client messages until the socket closes
+func (s *wsSession) readLoop() {
+	for {
+		messageType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Printf("WebSocket read failed: %v", err)
+			}
+			return
+		}
+		if messageType != websocket.TextMessage {
+			s.writeJSON(wsServerMessage{Type: "error", Error: "client messages must be JSON text frames"})
+			continue
+		}
+
+		var msg wsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.writeJSON(wsServerMessage{Type: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+
+		switch msg.Type {
+		case "subscribe":
+			if err := s.subscribe(msg); err != nil {
+				s.writeJSON(wsServerMessage{Type: "error", ID: msg.ID, Error: err.Error()})
+			}
+		case "cancel":
+			s.mu.Lock()
+			if cancel, ok := s.subscriptions[msg.ID]; ok {
+				cancel()
+			}
+			s.mu.Unlock()
+		default:
+			s.writeJSON(wsServerMessage{Type: "error", ID: msg.ID, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
+
+// subscribe validates a subscription and starts its stream
+func (s *wsSession) subscribe(msg wsClientMessage) error {
+	if msg.ID == "" {
+		return fmt.Errorf("subscribe requires an id")
+	}
+	if msg.Format == "" {
+		msg.Format = "json"
+	}
+	if msg.Format != "json" && msg.Format != "proto" {
+		return fmt.Errorf("format %q is not one of json, proto", msg.Format)
+	}
+	if msg.Transport == "" {
+		msg.Transport = transportGRPC
+	}
+	source, err := s.gateway.source(msg.Transport)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.StreamRequest{ChunkSize: msg.ChunkSize}
+	if req.ChunkSize <= 0 {
+		req.ChunkSize = catalog.DefaultChunkSize
+	}
+	if len(msg.Filter) > 0 {
+		req.Filter = &pb.HotelFilter{}
+		if err := protojson.Unmarshal(msg.Filter, req.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %v", err)
+		}
+	}
+	if len(msg.Fields) > 0 {
+		req.Fields = &fieldmaskpb.FieldMask{Paths: msg.Fields}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.subscriptions[msg.ID]; exists {
+		return fmt.Errorf("subscription %q is already active", msg.ID)
+	}
+	if len(s.subscriptions) >= maxSubscriptionsPerSocket {
+		return fmt.Errorf("at most %d concurrent subscriptions per socket", maxSubscriptionsPerSocket)
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.subscriptions[msg.ID] = cancel
+	s.wg.Add(1)
+	go s.run(ctx, cancel, msg.ID, msg.Format, source, req)
+	return nil
+}
+
+// run forwards one hotel stream to the socket
+func (s *wsSession) run(ctx context.Context, cancel context.CancelFunc, id, format string, source HotelSource, req *pb.StreamRequest) {
+	defer s.wg.Done()
+	defer cancel()
+
+	chunks := 0
+	var writeErr error
+	err := source.Stream(ctx, req, func(chunk *pb.HotelChunk) error {
+		if format == "proto" {
+			writeErr = s.writeChunkBinary(id, chunk)
+		} else {
+			writeErr = s.writeChunkJSON(id, chunk)
+		}
+		if writeErr != nil {
+			return writeErr
+		}
+		chunks++
+		return nil
+	})
+
+	// Free the id before the final frame, so a client may reuse it as soon
+	// as it sees "done" or "cancelled"
+	s.mu.Lock()
+	delete(s.subscriptions, id)
+	s.mu.Unlock()
+
+	switch {
+	case writeErr != nil:
+		log.Printf("WebSocket write failed for subscription %q: %v", id, writeErr)
+	case err != nil:
+		s.reportEnd(ctx, id, chunks, err)
+	default:
+		s.writeJSON(wsServerMessage{Type: "done", ID: id, Chunks: chunks})
+	}
+}
+
+// reportEnd tells the client why a subscription stopped early
+func (s *wsSession) reportEnd(ctx context.Context, id string, chunks int, err error) {
+	switch {
+	case s.ctx.Err() != nil:
+		// The socket is gone, nobody to report to
+	case ctx.Err() != nil:
+		s.writeJSON(wsServerMessage{Type: "cancelled", ID: id, Chunks: chunks})
+	default:
+		s.writeJSON(wsServerMessage{Type: "error", ID: id, Error: status.Convert(err).Message()})
+	}
+}
+
+func (s *wsSession) writeChunkJSON(id string, chunk *pb.HotelChunk) error {
+	encoded, err := protojson.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	return s.writeJSON(wsServerMessage{Type: "chunk", ID: id, Chunk: encoded})
+}
+
+func (s *wsSession) writeChunkBinary(id string, chunk *pb.HotelChunk) error {
+	frame := binary.AppendUvarint(nil, uint64(len(id)))
+	frame = append(frame, id...)
+	frame, err := proto.MarshalOptions{}.MarshalAppend(frame, chunk)
+	if err != nil {
+		return err
+	}
+	return s.write(websocket.BinaryMessage, frame)
+}
+
+func (s *wsSession) writeJSON(msg wsServerMessage) error {
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.write(websocket.TextMessage, encoded)
+}
+
+func (s *wsSession) write(messageType int, data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return s.conn.WriteMessage(messageType, data)
+}
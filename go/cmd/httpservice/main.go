@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/hotelquery"
+	pb "grpc-vs-http/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// DataResponse is the full dataset, in the same shape as data.json and the
+// Node.js microservice's /data response
+type DataResponse struct {
+	Hotels   []*pb.Hotel  `json:"hotels"`
+	Metadata *pb.Metadata `json:"metadata"`
+}
+
+// mimeProtobuf is the content type of length-delimited HotelChunk streams
+const mimeProtobuf = "application/x-protobuf"
+
+// streamErrorTrailer carries the error of a stream that failed after its
+// first chunk
+const streamErrorTrailer = "X-Stream-Error"
+
+// chunkWriter encodes the chunks of /data/chunked in one format
+type chunkWriter interface {
+	contentType() string
+	writeChunk(chunk *pb.HotelChunk) error
+	writeError(err error)
+}
+
+// ndjsonWriter writes one JSON HotelChunk per line and ends a failed stream
+// with an {"error": ...} line
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w ndjsonWriter) contentType() string { return "application/x-ndjson" }
+
+func (w ndjsonWriter) writeChunk(chunk *pb.HotelChunk) error { return w.encoder.Encode(chunk) }
+
+func (w ndjsonWriter) writeError(err error) { w.encoder.Encode(gin.H{"error": err.Error()}) }
+
+// protoWriter writes each HotelChunk in protobuf wire format, prefixed by
+// its size as a uvarint
+type protoWriter struct {
+	w io.Writer
+}
+
+func (w protoWriter) contentType() string { return mimeProtobuf }
+
+func (w protoWriter) writeChunk(chunk *pb.HotelChunk) error {
+	_, err := protodelim.MarshalTo(w.w, chunk)
+	return err
+}
+
+func (w protoWriter) writeError(err error) {}
+
+// HTTPService serves the hotel catalog over plain HTTP/JSON, as the
+// counterpart of the gRPC microservice
+type HTTPService struct {
+	catalog *catalog.Catalog
+}
+
+// NewHTTPService creates a new HTTP service for the given catalog
+func NewHTTPService(cat *catalog.Catalog) *HTTPService {
+	return &HTTPService{catalog: cat}
+}
+
+// handleData returns the whole dataset in one JSON document. Like the
+// Node.js microservice it is encoded again on every request.
+func (h *HTTPService) handleData(c *gin.Context) {
+	c.JSON(http.StatusOK, DataResponse{
+		Hotels:   h.catalog.Hotels,
+		Metadata: h.catalog.Metadata,
+	})
+}
+
+// handleDataChunked streams the hotels one HotelChunk at a time, with the
+// same chunking, filter and field mask semantics as GetHotelsStreaming.
+// Chunks are newline-delimited JSON, or varint length-delimited protobuf
+// when the client sends Accept: application/x-protobuf.
+func (h *HTTPService) handleDataChunked(c *gin.Context) {
+	req, err := hotelquery.StreamRequest(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var w chunkWriter = ndjsonWriter{json.NewEncoder(c.Writer)}
+	if c.NegotiateFormat(binding.MIMEJSON, mimeProtobuf) == mimeProtobuf {
+		w = protoWriter{c.Writer}
+	}
+
+	started := false
+	start := func() {
+		c.Header("Content-Type", w.contentType())
+		// A protobuf stream cannot carry an error message in-band, so
+		// failures after the first chunk are reported in a trailer
+		c.Header("Trailer", streamErrorTrailer)
+		c.Status(http.StatusOK)
+		started = true
+	}
+	err = h.catalog.Stream(req, func(chunk *pb.HotelChunk) error {
+		if !started {
+			start()
+		}
+		if err := w.writeChunk(chunk); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	})
+
+	switch {
+	case err == nil && !started:
+		// No matching hotels, the stream is empty
+		start()
+	case err != nil && !started:
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+	case err != nil:
+		log.Printf("Chunked stream aborted: %v", err)
+		if c.Request.Context().Err() == nil {
+			w.writeError(err)
+			c.Writer.Header().Set(streamErrorTrailer, err.Error())
+		}
+	}
+}
+
+// handleDataPage returns one page of hotels, with the same page size and
+// page token semantics as ListHotels
+func (h *HTTPService) handleDataPage(c *gin.Context) {
+	req, err := hotelquery.ListHotelsRequest(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.catalog.Page(req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// httpStatus maps catalog errors to HTTP status codes
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, catalog.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, catalog.ErrStalePageToken):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// setupRoutes configures the HTTP routes
+func (h *HTTPService) setupRoutes() *gin.Engine {
+	r := gin.Default()
+
+	// Whole dataset in one response, like the Node.js microservice
+	r.GET("/data", h.handleData)
+
+	// Chunks as newline-delimited JSON or delimited protobuf
+	r.GET("/data/chunked", h.handleDataChunked)
+
+	// Paginated listing
+	r.GET("/data/page", h.handleDataPage)
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	})
+
+	return r
+}
+
+func main() {
+	var dataPaths catalog.PathList
+	flag.Var(&dataPaths, "data", "data file or directory of *.json files; repeat or comma-separate to merge several (default: $"+catalog.EnvDataPath+", then ./data.json probes)")
+	addr := flag.String("addr", ":3002", "address to listen on")
+	flag.Parse()
+
+	source, err := catalog.Resolve(dataPaths, os.Getenv(catalog.EnvDataPath))
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+	log.Printf("Using data source: %s", source)
+
+	cat, err := source.Load()
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+	log.Printf("Loaded %d hotels", len(cat.Hotels))
+
+	service := NewHTTPService(cat)
+	router := service.setupRoutes()
+
+	log.Printf("HTTP microservice running on %s", *addr)
+	log.Println("Endpoints:")
+	log.Println("  - GET /data (whole dataset as one JSON document)")
+	log.Println("  - GET /data/chunked?chunkSize=<size> (NDJSON chunks, or delimited protobuf with Accept: application/x-protobuf)")
+	log.Println("  - GET /data/page?pageSize=<size>&pageToken=<token> (paginated listing, default: 100 per page)")
+	log.Println("  - GET /health (health check)")
+
+	if err := router.Run(*addr); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	targetURL := flag.String("target", "http://localhost:8080/stats", "http(s):// URL to GET, or grpc://host:port to stream GetHotelsStreaming from the microservice")
+	query := flag.String("query", "", "grpc:// targets only: chunkSize, filter and fields as a query string, e.g. chunkSize=500&country=FR")
+	duration := flag.Duration("duration", 10*time.Second, "length of the measured phase (ignored when -requests is set)")
+	requests := flag.Int("requests", 0, "number of measured requests, instead of a fixed -duration")
+	warmup := flag.Duration("warmup", 0, "run the load this long before measuring and discard the results")
+	concurrency := flag.Int("concurrency", 10, "closed model: concurrent workers; open model: maximum requests in flight")
+	rate := flag.Float64("rate", 0, "open model: start this many requests per second regardless of completions (0 = closed model)")
+	timeout := flag.Duration("timeout", 30*time.Second, "per-request timeout")
+	latencies := flag.String("latencies", "", "write every measured request (start, latency, bytes, error) to this CSV file")
+	histogram := flag.String("histogram", "", "write the latency distribution of successful requests to this .hgrm file")
+	sweep := flag.String("sweep", "", "run the load once per chunk size, e.g. 10,50,100-1000:100, and compare them")
+	sweepCSV := flag.String("sweep-csv", "", "-sweep only: write one row per chunk size to this CSV file")
+	flag.Parse()
+
+	if *concurrency <= 0 {
+		log.Fatalf("Invalid -concurrency: must be positive")
+	}
+	if *rate < 0 {
+		log.Fatalf("Invalid -rate: must not be negative")
+	}
+
+	runner := &Runner{
+		Timeout:     *timeout,
+		Concurrency: *concurrency,
+		Rate:        *rate,
+	}
+	model := fmt.Sprintf("closed, %d workers", *concurrency)
+	if *rate > 0 {
+		model = fmt.Sprintf("open, %.1f req/s, at most %d in flight", *rate, *concurrency)
+	}
+
+	// Ctrl-C ends the current phase early; the report covers what ran
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	phase := Phase{Duration: *duration}
+	if *requests > 0 {
+		phase = Phase{Requests: *requests}
+	}
+
+	if *sweep != "" {
+		sizes, err := parseChunkSizes(*sweep)
+		if err != nil {
+			log.Fatalf("Invalid -sweep: %v", err)
+		}
+		log.Printf("Sweeping %d chunk sizes on %s (%s)", len(sizes), *targetURL, model)
+		rows, err := runSweep(ctx, *runner, *targetURL, *query, sizes, phase, Phase{Duration: *warmup})
+		if err != nil {
+			log.Fatalf("Invalid -target: %v", err)
+		}
+		printSweep(os.Stdout, *targetURL, model, rows)
+
+		if *sweepCSV != "" {
+			if err := writeSweep(*sweepCSV, rows); err != nil {
+				log.Fatalf("Failed to write sweep: %v", err)
+			}
+			log.Printf("Wrote %d chunk sizes to %s", len(rows), *sweepCSV)
+		}
+		return
+	}
+
+	target, err := newTarget(*targetURL, *query, *concurrency)
+	if err != nil {
+		log.Fatalf("Invalid -target: %v", err)
+	}
+	defer target.Close()
+	runner.Target = target
+
+	if *warmup > 0 {
+		log.Printf("Warming up %s for %s (%s)", *targetURL, *warmup, model)
+		warm := runner.Run(ctx, Phase{Duration: *warmup})
+		log.Printf("Warmup done: %d requests", len(warm.Samples))
+	}
+
+	if *requests > 0 {
+		log.Printf("Sending %d requests to %s (%s)", *requests, *targetURL, model)
+	} else {
+		log.Printf("Loading %s for %s (%s)", *targetURL, *duration, model)
+	}
+
+	collected := runner.Run(ctx, phase)
+	printReport(os.Stdout, *targetURL, model, collected)
+
+	if *latencies != "" {
+		if err := writeSamples(*latencies, collected.Samples); err != nil {
+			log.Fatalf("Failed to write latencies: %v", err)
+		}
+		log.Printf("Wrote %d samples to %s", len(collected.Samples), *latencies)
+	}
+	if *histogram != "" {
+		if err := writeHistogram(*histogram, collected.Samples); err != nil {
+			log.Fatalf("Failed to write histogram: %v", err)
+		}
+		log.Printf("Wrote latency histogram to %s", *histogram)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"grpc-vs-http/internal/latency"
+)
+
+// successHistogram records the latency of every successful request
+func successHistogram(samples []Sample) *latency.Histogram {
+	histogram := latency.NewHistogram()
+	for _, sample := range samples {
+		if sample.Err == nil {
+			histogram.Record(sample.Latency)
+		}
+	}
+	return histogram
+}
+
+// printReport writes a human-readable summary of the measured phase
+func printReport(w io.Writer, target, model string, collected Collected) {
+	var failed int
+	var bytes int64
+	errors := make(map[string]int)
+	for _, sample := range collected.Samples {
+		bytes += sample.Bytes
+		if sample.Err != nil {
+			failed++
+			errors[sample.Err.Error()]++
+		}
+	}
+
+	seconds := collected.Elapsed.Seconds()
+	fmt.Fprintf(w, "Target:      %s\n", target)
+	fmt.Fprintf(w, "Model:       %s\n", model)
+	fmt.Fprintf(w, "Elapsed:     %s\n", collected.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Requests:    %d (%d failed", len(collected.Samples), failed)
+	if collected.Dropped > 0 {
+		fmt.Fprintf(w, ", %d not sent: too many in flight", collected.Dropped)
+	}
+	fmt.Fprintln(w, ")")
+	if seconds > 0 {
+		fmt.Fprintf(w, "Throughput:  %.1f req/s, %.2f MB/s\n",
+			float64(len(collected.Samples))/seconds, float64(bytes)/(1024*1024)/seconds)
+	}
+
+	if summary := successHistogram(collected.Samples).Summary(); summary != nil {
+		fmt.Fprintf(w, "Latency:     min %s  mean %s  stddev %s  max %s\n",
+			formatMicros(float64(summary.MinUs)),
+			formatMicros(summary.MeanUs),
+			formatMicros(summary.StdDevUs),
+			formatMicros(float64(summary.MaxUs)))
+		fmt.Fprintf(w, "Percentiles: p50 %s  p90 %s  p95 %s  p99 %s  p99.9 %s\n",
+			formatMicros(float64(summary.P50Us)),
+			formatMicros(float64(summary.P90Us)),
+			formatMicros(float64(summary.P95Us)),
+			formatMicros(float64(summary.P99Us)),
+			formatMicros(float64(summary.P999Us)))
+	}
+
+	if len(errors) > 0 {
+		messages := make([]string, 0, len(errors))
+		for message := range errors {
+			messages = append(messages, message)
+		}
+		sort.Slice(messages, func(i, j int) bool { return errors[messages[i]] > errors[messages[j]] })
+
+		fmt.Fprintln(w, "Errors:")
+		for _, message := range messages {
+			fmt.Fprintf(w, "  %6d  %s\n", errors[message], message)
+		}
+	}
+}
+
+func formatMicros(us float64) string {
+	return strconv.FormatFloat(us/1000, 'f', 2, 64) + "ms"
+}
+
+// writeHistogram saves the latency distribution of the successful requests
+// in the .hgrm format, for HdrHistogram's plotting tools
+func writeHistogram(path string, samples []Sample) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := successHistogram(samples).WritePercentiles(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeSamples saves every request of the measured phase as CSV, one row
+// per request, for plotting or offline analysis
+func writeSamples(path string, samples []Sample) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	sorted := append([]Sample(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"start_us", "latency_us", "bytes", "error"})
+	for _, sample := range sorted {
+		errText := ""
+		if sample.Err != nil {
+			errText = sample.Err.Error()
+		}
+		w.Write([]string{
+			strconv.FormatInt(sample.Start.Microseconds(), 10),
+			strconv.FormatInt(sample.Latency.Microseconds(), 10),
+			strconv.FormatInt(sample.Bytes, 10),
+			errText,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sample is the latency capture of one request
+type Sample struct {
+	Start    time.Duration // Offset of the (scheduled) start from the beginning of the phase
+	Latency  time.Duration
+	Bytes    int64
+	Messages int
+	Err      error
+}
+
+// Phase bounds one run of the load, by duration or by request count
+type Phase struct {
+	Duration time.Duration // Stop issuing requests after this long (0 = unbounded)
+	Requests int           // Stop after this many requests (0 = unbounded)
+}
+
+// Runner drives a target with one of the two load models
+type Runner struct {
+	Target      Target
+	Timeout     time.Duration // Per-request timeout
+	Concurrency int           // Closed model: workers; open model: cap on requests in flight
+	Rate        float64       // Open model: requests per second (0 = closed model)
+}
+
+// Collected holds the samples of one phase
+type Collected struct {
+	Samples []Sample
+	Elapsed time.Duration
+	Dropped int // Open model: requests not sent because Concurrency were in flight
+}
+
+// Run executes one phase and collects a sample per request
+func (r *Runner) Run(ctx context.Context, phase Phase) Collected {
+	if r.Rate > 0 {
+		return r.runOpen(ctx, phase)
+	}
+	return r.runClosed(ctx, phase)
+}
+
+// runClosed keeps Concurrency requests in flight: every worker issues its
+// next request as soon as the previous one completes, so a slower server
+// automatically receives less load. Requests in flight when the phase ends
+// run to completion and are recorded, as in the open model; only an
+// interrupted run cuts them off.
+func (r *Runner) runClosed(ctx context.Context, phase Phase) Collected {
+	issuing, cancel := phaseContext(ctx, phase)
+	defer cancel()
+
+	start := time.Now()
+	var issued atomic.Int64
+	var mu sync.Mutex
+	var samples []Sample
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for issuing.Err() == nil {
+				if phase.Requests > 0 && issued.Add(1) > int64(phase.Requests) {
+					return
+				}
+				requestStart := time.Now()
+				sample := r.do(ctx, requestStart)
+				if sample.Err != nil && ctx.Err() != nil {
+					return // Cut off by an interrupt, not a failure
+				}
+				sample.Start = requestStart.Sub(start)
+
+				mu.Lock()
+				samples = append(samples, sample)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return Collected{Samples: samples, Elapsed: time.Since(start)}
+}
+
+// runOpen starts requests at a constant Rate whether or not earlier ones
+// have completed, like independent users would. Latency is measured from the
+// scheduled start, so a stalled server or load generator shows up as latency
+// instead of silently lowering the rate (coordinated omission).
+func (r *Runner) runOpen(ctx context.Context, phase Phase) Collected {
+	start := time.Now()
+	interval := time.Duration(float64(time.Second) / r.Rate)
+
+	var inFlight atomic.Int64
+	var mu sync.Mutex
+	var samples []Sample
+	dropped := 0
+
+	var wg sync.WaitGroup
+	for i := 0; ; i++ {
+		if phase.Requests > 0 && i >= phase.Requests {
+			break
+		}
+		offset := time.Duration(i) * interval
+		if phase.Duration > 0 && offset >= phase.Duration {
+			break
+		}
+
+		scheduled := start.Add(offset)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Until(scheduled)):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if inFlight.Load() >= int64(r.Concurrency) {
+			dropped++
+			continue
+		}
+
+		inFlight.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer inFlight.Add(-1)
+
+			sample := r.do(ctx, scheduled)
+			sample.Start = scheduled.Sub(start)
+
+			mu.Lock()
+			samples = append(samples, sample)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return Collected{Samples: samples, Elapsed: time.Since(start), Dropped: dropped}
+}
+
+// do issues one request and measures its latency from since
+func (r *Runner) do(ctx context.Context, since time.Time) Sample {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	result := r.Target.Do(ctx)
+	return Sample{Latency: time.Since(since), Bytes: result.Bytes, Messages: result.Messages, Err: result.Err}
+}
+
+// phaseContext ends ctx when a duration-bounded phase is over, to stop
+// issuing requests
+func phaseContext(ctx context.Context, phase Phase) (context.Context, context.CancelFunc) {
+	if phase.Duration > 0 {
+		return context.WithTimeout(ctx, phase.Duration)
+	}
+	return context.WithCancel(ctx)
+}
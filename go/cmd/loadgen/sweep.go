@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"grpc-vs-http/internal/latency"
+)
+
+// SweepRow is the measured phase of one chunk size
+type SweepRow struct {
+	ChunkSize      int
+	Requests       int
+	Failed         int
+	Latency        *latency.Summary // Successful requests; nil when all failed
+	ReqPerSec      float64
+	MBPerSec       float64
+	MessagesPerReq float64 // grpc:// targets only
+	// Heap allocations of the load generator per request, i.e. the client
+	// side of the requests: not the target's allocations
+	ClientAllocsPerReq     float64
+	ClientAllocBytesPerReq float64
+}
+
+// parseChunkSizes parses a comma-separated list of chunk sizes and ranges,
+// e.g. "10,50,100-1000:100". A range without a step, "100-500", counts by 100.
+func parseChunkSizes(value string) ([]int, error) {
+	seen := make(map[int]bool)
+	var sizes []int
+	add := func(size int) {
+		if !seen[size] {
+			seen[size] = true
+			sizes = append(sizes, size)
+		}
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds, step, hasStep := strings.Cut(part, ":")
+		low, high, isRange := strings.Cut(bounds, "-")
+		if !isRange {
+			if hasStep {
+				return nil, fmt.Errorf("%q: a step needs a range, e.g. 100-1000:100", part)
+			}
+			high = low
+		}
+		if !hasStep {
+			step = "100"
+		}
+
+		from, err := strconv.Atoi(low)
+		if err != nil || from <= 0 {
+			return nil, fmt.Errorf("%q: chunk sizes must be positive integers", part)
+		}
+		to, err := strconv.Atoi(high)
+		if err != nil || to < from {
+			return nil, fmt.Errorf("%q: the end of a range must be an integer no less than its start", part)
+		}
+		by, err := strconv.Atoi(step)
+		if err != nil || by <= 0 {
+			return nil, fmt.Errorf("%q: the step must be a positive integer", part)
+		}
+
+		for size := from; size <= to; size += by {
+			add(size)
+		}
+	}
+
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("no chunk sizes given")
+	}
+	sort.Ints(sizes)
+	return sizes, nil
+}
+
+// withChunkSize sets the chunk size of a target: in -query for grpc://
+// targets, in the URL's query string for http(s)://
+func withChunkSize(rawURL, query string, chunkSize int) (string, string, error) {
+	size := strconv.Itoa(chunkSize)
+	if strings.HasPrefix(rawURL, "grpc://") {
+		values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+		if err != nil {
+			return "", "", fmt.Errorf("-query: %v", err)
+		}
+		values.Set("chunkSize", size)
+		return rawURL, values.Encode(), nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	values := parsed.Query()
+	values.Set("chunkSize", size)
+	parsed.RawQuery = values.Encode()
+	return parsed.String(), query, nil
+}
+
+// runSweep runs the same phase once per chunk size, each against a fresh
+// target, and measures it
+func runSweep(ctx context.Context, runner Runner, rawURL, query string, sizes []int, phase, warmup Phase) ([]SweepRow, error) {
+	var rows []SweepRow
+	for _, size := range sizes {
+		if ctx.Err() != nil {
+			break // Interrupted: report the sizes measured so far
+		}
+
+		sizeURL, sizeQuery, err := withChunkSize(rawURL, query, size)
+		if err != nil {
+			return nil, err
+		}
+		target, err := newTarget(sizeURL, sizeQuery, runner.Concurrency)
+		if err != nil {
+			return nil, err
+		}
+		runner.Target = target
+
+		if warmup.Duration > 0 {
+			runner.Run(ctx, warmup)
+		}
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		collected := runner.Run(ctx, phase)
+		runtime.ReadMemStats(&after)
+		target.Close()
+
+		row := sweepRow(size, collected)
+		if len(collected.Samples) > 0 {
+			requests := float64(len(collected.Samples))
+			row.ClientAllocsPerReq = float64(after.Mallocs-before.Mallocs) / requests
+			row.ClientAllocBytesPerReq = float64(after.TotalAlloc-before.TotalAlloc) / requests
+		}
+		log.Printf("chunkSize=%d: %d requests, %d failed", size, row.Requests, row.Failed)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// sweepRow summarizes the samples of one chunk size
+func sweepRow(size int, collected Collected) SweepRow {
+	row := SweepRow{ChunkSize: size, Requests: len(collected.Samples)}
+	var bytes int64
+	var messages int
+	for _, sample := range collected.Samples {
+		bytes += sample.Bytes
+		messages += sample.Messages
+		if sample.Err != nil {
+			row.Failed++
+		}
+	}
+	row.Latency = successHistogram(collected.Samples).Summary()
+
+	if seconds := collected.Elapsed.Seconds(); seconds > 0 {
+		row.ReqPerSec = float64(row.Requests-row.Failed) / seconds
+		row.MBPerSec = float64(bytes) / (1024 * 1024) / seconds
+	}
+	if row.Requests > 0 {
+		row.MessagesPerReq = float64(messages) / float64(row.Requests)
+	}
+	return row
+}
+
+// bestChunkSize returns the index of the row with the highest throughput
+// among those without failures, or -1
+func bestChunkSize(rows []SweepRow) int {
+	best := -1
+	for i, row := range rows {
+		if row.Failed > 0 || row.Latency == nil {
+			continue
+		}
+		if best < 0 || row.ReqPerSec > rows[best].ReqPerSec {
+			best = i
+		}
+	}
+	return best
+}
+
+// printSweep writes the sweep as a table and names the best chunk size
+func printSweep(w io.Writer, target, model string, rows []SweepRow) {
+	fmt.Fprintf(w, "Target:      %s\n", target)
+	fmt.Fprintf(w, "Model:       %s\n\n", model)
+
+	best := bestChunkSize(rows)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "chunkSize\trequests\tfailed\tp50\tp90\tp99\tp99.9\treq/s\tMB/s\tmsgs/req\tclient allocs/req\tclient KB alloc/req\t\t")
+	for i, row := range rows {
+		percentiles := "-\t-\t-\t-"
+		if summary := row.Latency; summary != nil {
+			percentiles = strings.Join([]string{
+				formatMicros(float64(summary.P50Us)),
+				formatMicros(float64(summary.P90Us)),
+				formatMicros(float64(summary.P99Us)),
+				formatMicros(float64(summary.P999Us)),
+			}, "\t")
+		}
+		mark := ""
+		if i == best {
+			mark = "best"
+		}
+		fmt.Fprintf(table, "%d\t%d\t%d\t%s\t%.1f\t%.2f\t%.1f\t%.0f\t%.1f\t%s\t\n",
+			row.ChunkSize, row.Requests, row.Failed, percentiles,
+			row.ReqPerSec, row.MBPerSec, row.MessagesPerReq,
+			row.ClientAllocsPerReq, row.ClientAllocBytesPerReq/1024, mark)
+	}
+	table.Flush()
+
+	if best < 0 {
+		fmt.Fprintln(w, "\nNo chunk size completed without failures")
+		return
+	}
+	fmt.Fprintf(w, "\nBest chunk size for this dataset: %d (%.1f req/s, p50 %s)\n",
+		rows[best].ChunkSize, rows[best].ReqPerSec, formatMicros(float64(rows[best].Latency.P50Us)))
+}
+
+// writeSweep saves the sweep as CSV, one row per chunk size
+func writeSweep(path string, rows []SweepRow) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	best := bestChunkSize(rows)
+	w := csv.NewWriter(file)
+	w.Write([]string{
+		"chunk_size", "requests", "failed",
+		"p50_us", "p90_us", "p95_us", "p99_us", "p999_us", "mean_us", "stddev_us",
+		"req_per_sec", "mb_per_sec", "messages_per_req", "client_allocs_per_req", "client_alloc_bytes_per_req", "best",
+	})
+	for i, row := range rows {
+		percentiles := make([]string, 7)
+		if summary := row.Latency; summary != nil {
+			percentiles = []string{
+				strconv.FormatInt(summary.P50Us, 10),
+				strconv.FormatInt(summary.P90Us, 10),
+				strconv.FormatInt(summary.P95Us, 10),
+				strconv.FormatInt(summary.P99Us, 10),
+				strconv.FormatInt(summary.P999Us, 10),
+				strconv.FormatFloat(summary.MeanUs, 'f', 0, 64),
+				strconv.FormatFloat(summary.StdDevUs, 'f', 0, 64),
+			}
+		}
+		record := []string{strconv.Itoa(row.ChunkSize), strconv.Itoa(row.Requests), strconv.Itoa(row.Failed)}
+		record = append(record, percentiles...)
+		record = append(record,
+			strconv.FormatFloat(row.ReqPerSec, 'f', 2, 64),
+			strconv.FormatFloat(row.MBPerSec, 'f', 2, 64),
+			strconv.FormatFloat(row.MessagesPerReq, 'f', 1, 64),
+			strconv.FormatFloat(row.ClientAllocsPerReq, 'f', 0, 64),
+			strconv.FormatFloat(row.ClientAllocBytesPerReq, 'f', 0, 64),
+			strconv.FormatBool(i == best),
+		)
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
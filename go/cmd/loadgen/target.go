@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"grpc-vs-http/internal/hotelquery"
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// Result is the outcome of one request
+type Result struct {
+	Bytes    int64 // Response bytes received: the body for HTTP, the messages as framed on the wire for gRPC
+	Messages int   // Stream messages received, for grpc:// targets
+	Err      error
+}
+
+// Target issues one request and reads the whole response
+type Target interface {
+	Do(ctx context.Context) Result
+	Close() error
+}
+
+// newTarget picks the target implementation from the URL scheme:
+// http(s):// URLs are fetched with GET, grpc://host:port streams
+// GetHotelsStreaming from the microservice directly, using query for the
+// chunk size, filters and fields.
+func newTarget(rawURL, query string, concurrency int) (Target, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+		if query != "" {
+			return nil, fmt.Errorf("-query only applies to grpc:// targets, put HTTP parameters in the URL")
+		}
+		return newHTTPTarget(rawURL, concurrency), nil
+	case "grpc":
+		values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+		if err != nil {
+			return nil, fmt.Errorf("-query: %v", err)
+		}
+		req, err := hotelquery.StreamRequest(values)
+		if err != nil {
+			return nil, fmt.Errorf("-query: %v", err)
+		}
+		return newGRPCTarget(parsed.Host, req)
+	default:
+		return nil, fmt.Errorf("unsupported target scheme %q (want http, https or grpc)", parsed.Scheme)
+	}
+}
+
+// httpTarget fetches a URL, e.g. a gateway endpoint or the HTTP microservice
+type httpTarget struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPTarget(url string, concurrency int) *httpTarget {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Keep one idle connection per worker instead of reconnecting
+	transport.MaxIdleConns = concurrency
+	transport.MaxIdleConnsPerHost = concurrency
+	return &httpTarget{url: url, client: &http.Client{Transport: transport}}
+}
+
+func (t *httpTarget) Do(ctx context.Context) Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return Result{Bytes: n, Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{Bytes: n, Err: fmt.Errorf("HTTP %d", resp.StatusCode)}
+	}
+	return Result{Bytes: n}
+}
+
+func (t *httpTarget) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// grpcTarget reads a whole GetHotelsStreaming call from the microservice
+type grpcTarget struct {
+	conn   *grpc.ClientConn
+	client pb.DataServiceClient
+	req    *pb.StreamRequest
+}
+
+func newGRPCTarget(addr string, req *pb.StreamRequest) (*grpcTarget, error) {
+	// Same client settings as the gateway
+	kacp := keepalive.ClientParameters{
+		Time:                2 * time.Minute,
+		Timeout:             20 * time.Second,
+		PermitWithoutStream: true,
+	}
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(kacp),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1000*1024*1024)),
+		// Counts received bytes as gRPC reads them off the wire, instead of
+		// re-encoding every chunk inside the timed request
+		grpc.WithStatsHandler(metrics.ClientWireStats{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcTarget{conn: conn, client: pb.NewDataServiceClient(conn), req: req}, nil
+}
+
+func (t *grpcTarget) Do(ctx context.Context) Result {
+	ctx, wire := metrics.WithWireCounter(ctx)
+	stream, err := t.client.GetHotelsStreaming(ctx, t.req)
+	if err != nil {
+		return Result{Err: grpcError(err)}
+	}
+
+	var result Result
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			result.Bytes = wire.Bytes()
+			return result
+		}
+		if err != nil {
+			result.Bytes = wire.Bytes()
+			result.Err = grpcError(err)
+			return result
+		}
+		result.Messages++
+	}
+}
+
+func (t *grpcTarget) Close() error {
+	return t.conn.Close()
+}
+
+// grpcError formats a gRPC error as "Code: message", so failures group by status
+func grpcError(err error) error {
+	st := status.Convert(err)
+	return fmt.Errorf("%s: %s", st.Code(), st.Message())
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/codec"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+// rawChunk is a HotelChunk marshaled ahead of time, sent as is by rawCodec
+type rawChunk []byte
+
+// cachedChunk is a rawChunk and the number of hotels it holds
+type cachedChunk struct {
+	data   rawChunk
+	hotels int
+}
+
+// rawCodec replaces the registered "proto" codec: rawChunk messages are
+// already marshaled and pass through unchanged, everything else goes to
+// gRPC's own protobuf codec. Registering it rather than forcing a codec on
+// the server lets calls still pick another codec by content-subtype.
+type rawCodec struct {
+	proto encoding.Codec // gRPC's protobuf codec, registered before ours
+}
+
+func init() {
+	encoding.RegisterCodec(rawCodec{proto: encoding.GetCodec(codec.Proto)})
+}
+
+func (rawCodec) Name() string {
+	return codec.Proto
+}
+
+func (c rawCodec) Marshal(v any) ([]byte, error) {
+	if chunk, ok := v.(rawChunk); ok {
+		return chunk, nil
+	}
+	return c.proto.Marshal(v)
+}
+
+func (c rawCodec) Unmarshal(data []byte, v any) error {
+	return c.proto.Unmarshal(data, v)
+}
+
+// chunkCacheConfig selects what a chunkCache holds, from --chunk-cache and
+// --chunk-cache-compression
+type chunkCacheConfig struct {
+	sizes       []int32
+	compressors []string // Compressed copies are only kept for these
+}
+
+// parseChunkCacheConfig parses comma-separated chunk sizes and compressor
+// names. It returns nil when no chunk size is given.
+func parseChunkCacheConfig(sizes, compressors string) (*chunkCacheConfig, error) {
+	config := &chunkCacheConfig{}
+	for _, value := range strings.Split(sizes, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("--chunk-cache: %q is not a positive integer", value)
+		}
+		config.sizes = append(config.sizes, int32(size))
+	}
+	for _, name := range strings.Split(compressors, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if encoding.GetCompressor(name) == nil {
+			return nil, fmt.Errorf("--chunk-cache-compression: %q is not a registered compressor", name)
+		}
+		config.compressors = append(config.compressors, name)
+	}
+
+	if len(config.sizes) == 0 {
+		if len(config.compressors) > 0 {
+			return nil, fmt.Errorf("--chunk-cache-compression needs --chunk-cache")
+		}
+		return nil, nil
+	}
+	return config, nil
+}
+
+// chunkCache holds the chunks of unfiltered GetHotelsStreaming calls,
+// marshaled once per catalog snapshot for the configured chunk sizes.
+// Compressed copies are built by the first call that asks for them.
+type chunkCache struct {
+	catalog     *catalog.Catalog // Snapshot the chunks were built from
+	chunks      map[int32][]cachedChunk
+	compressors []string
+	prepared    sync.Map // preparedKey -> *preparedChunks
+}
+
+type preparedKey struct {
+	chunkSize  int32
+	compressor string
+}
+
+// preparedChunks are the chunks of one size, compressed and framed for
+// gRPC with one compressor
+type preparedChunks struct {
+	once sync.Once
+	msgs []*grpc.PreparedMsg
+	err  error
+}
+
+// newChunkCache marshals the chunks of cat for every configured size
+func newChunkCache(cat *catalog.Catalog, config *chunkCacheConfig) (*chunkCache, error) {
+	cache := &chunkCache{
+		catalog:     cat,
+		chunks:      make(map[int32][]cachedChunk),
+		compressors: config.compressors,
+	}
+
+	var total int64
+	for _, size := range config.sizes {
+		start := time.Now()
+		var chunks []cachedChunk
+		var bytes int64
+		err := cat.Stream(&pb.StreamRequest{ChunkSize: size}, func(chunk *pb.HotelChunk) error {
+			data, err := proto.Marshal(chunk)
+			if err != nil {
+				return err
+			}
+			chunks = append(chunks, cachedChunk{data: data, hotels: len(chunk.Hotels)})
+			bytes += int64(len(data))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		cache.chunks[size] = chunks
+		total += bytes
+		log.Printf("Chunk cache: chunkSize=%d, %d chunks, %s, marshaled once in %s",
+			size, len(chunks), formatBytes(bytes), time.Since(start).Round(time.Microsecond))
+	}
+	log.Printf("Chunk cache: %s for %d chunk size(s)", formatBytes(total), len(config.sizes))
+	return cache, nil
+}
+
+// lookup returns the cached chunks for req, or nil when req filters,
+// projects, uses a chunk size that is not cached or was sent with another
+// codec than protobuf
+func (c *chunkCache) lookup(ctx context.Context, req *pb.StreamRequest) []cachedChunk {
+	if !catalog.IsEmptyFilter(req.GetFilter()) || len(req.GetFields().GetPaths()) > 0 {
+		return nil
+	}
+	if subtype := contentSubtype(ctx); subtype != "" && subtype != codec.Proto {
+		return nil
+	}
+	return c.chunks[chunkSize(req)]
+}
+
+// chunkSize returns the chunk size req is served with
+func chunkSize(req *pb.StreamRequest) int32 {
+	if size := req.GetChunkSize(); size > 0 {
+		return size
+	}
+	return catalog.DefaultChunkSize
+}
+
+// send streams cached chunks and reports them to observer. Calls
+// compressed with one of the cache's compressors get compressed copies,
+// others let gRPC compress as usual.
+func (c *chunkCache) send(stream grpc.ServerStream, req *pb.StreamRequest, chunks []cachedChunk, observer *chunkObserver) error {
+	compressor := sendCompressor(stream.Context())
+	if !slices.Contains(c.compressors, compressor) {
+		for _, chunk := range chunks {
+			if err := stream.SendMsg(chunk.data); err != nil {
+				return err
+			}
+			observer.sent(chunk.hotels)
+		}
+		return nil
+	}
+
+	msgs, err := c.prepare(stream, preparedKey{chunkSize: chunkSize(req), compressor: compressor}, chunks)
+	if err != nil {
+		return err
+	}
+	for i, msg := range msgs {
+		if err := stream.SendMsg(msg); err != nil {
+			return err
+		}
+		observer.sent(chunks[i].hotels)
+	}
+	return nil
+}
+
+// prepare returns the compressed copies of chunks, building them on the
+// first call. grpc.PreparedMsg takes the compressor from the stream, so the
+// first call with each compressor does the work for the later ones.
+func (c *chunkCache) prepare(stream grpc.ServerStream, key preparedKey, chunks []cachedChunk) ([]*grpc.PreparedMsg, error) {
+	value, _ := c.prepared.LoadOrStore(key, &preparedChunks{})
+	prepared := value.(*preparedChunks)
+
+	prepared.once.Do(func() {
+		start := time.Now()
+		for _, chunk := range chunks {
+			msg := &grpc.PreparedMsg{}
+			if err := msg.Encode(stream, chunk.data); err != nil {
+				prepared.err = err
+				return
+			}
+			prepared.msgs = append(prepared.msgs, msg)
+		}
+
+		// PreparedMsg does not expose its size, compress once more to report it
+		raw, compressed := compressedSize(key.compressor, chunks)
+		log.Printf("Chunk cache: chunkSize=%d compressed with %s, %s (%.0f%% of %s), in %s",
+			key.chunkSize, key.compressor, formatBytes(compressed), 100*float64(compressed)/float64(raw),
+			formatBytes(raw), time.Since(start).Round(time.Microsecond))
+	})
+	return prepared.msgs, prepared.err
+}
+
+// sendCompressor returns the compressor gRPC compresses the responses of a
+// call with: the one the client compressed its request with, or "" for none
+func sendCompressor(ctx context.Context) string {
+	// grpc-encoding is not part of the incoming metadata, only the
+	// transport stream knows it
+	stream, ok := grpc.ServerTransportStreamFromContext(ctx).(interface{ RecvCompress() string })
+	if !ok {
+		return ""
+	}
+	name := stream.RecvCompress()
+	if name == "" || name == "identity" || encoding.GetCompressor(name) == nil {
+		return ""
+	}
+	return name
+}
+
+// contentSubtype returns the codec a call was made with, "" for the default
+func contentSubtype(ctx context.Context) string {
+	stream, ok := grpc.ServerTransportStreamFromContext(ctx).(interface{ ContentSubtype() string })
+	if !ok {
+		return ""
+	}
+	return stream.ContentSubtype()
+}
+
+// compressedSize compresses chunks with the named compressor and returns
+// their size before and after
+func compressedSize(name string, chunks []cachedChunk) (raw, compressed int64) {
+	compressor := encoding.GetCompressor(name)
+	for _, chunk := range chunks {
+		counter := &countingWriter{}
+		w, err := compressor.Compress(counter)
+		if err != nil {
+			continue
+		}
+		w.Write(chunk.data)
+		w.Close()
+		raw += int64(len(chunk.data))
+		compressed += counter.n
+	}
+	return raw, compressed
+}
+
+// countingWriter discards what is written to it and counts the bytes
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func formatBytes(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"grpc-vs-http/internal/compression"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChunkCacheMatchesUncached(t *testing.T) {
+	cacheConfig, err := parseChunkCacheConfig("10", compression.Gzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, uncached := serveGRPC(t, testServer(t, nil, 25))
+	_, cached := serveGRPC(t, testServer(t, cacheConfig, 25))
+
+	tests := []struct {
+		name string
+		req  *pb.StreamRequest
+		opts []grpc.CallOption
+	}{
+		{name: "cached size", req: &pb.StreamRequest{ChunkSize: 10}},
+		{name: "cached size, compressed", req: &pb.StreamRequest{ChunkSize: 10}, opts: []grpc.CallOption{grpc.UseCompressor(compression.Gzip)}},
+		{name: "size not cached", req: &pb.StreamRequest{ChunkSize: 7}},
+		{name: "filtered", req: &pb.StreamRequest{ChunkSize: 10, Filter: &pb.HotelFilter{Available: proto.Bool(true)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := streamChunks(t, uncached, tt.req, tt.opts...)
+			got := streamChunks(t, cached, tt.req, tt.opts...)
+			if len(got) != len(want) {
+				t.Fatalf("got %d chunks, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !proto.Equal(got[i], want[i]) {
+					t.Fatalf("chunk %d differs from the uncached chunk", i)
+				}
+			}
+		})
+	}
+}
+
+// streamChunks collects the chunks of a GetHotelsStreaming call
+func streamChunks(tb testing.TB, client pb.DataServiceClient, req *pb.StreamRequest, opts ...grpc.CallOption) []*pb.HotelChunk {
+	tb.Helper()
+
+	stream, err := client.GetHotelsStreaming(context.Background(), req, opts...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var chunks []*pb.HotelChunk
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks
+		}
+		if err != nil {
+			tb.Fatalf("stream: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+// BenchmarkGetHotelsStreaming compares whole unfiltered streams served with
+// and without the chunk cache, client decoding included:
+//
+//	go test -bench GetHotelsStreaming -benchmem ./cmd/microservice
+func BenchmarkGetHotelsStreaming(b *testing.B) {
+	cacheConfig, err := parseChunkCacheConfig("100", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	req := &pb.StreamRequest{ChunkSize: 100}
+
+	for _, bench := range []struct {
+		name        string
+		cacheConfig *chunkCacheConfig
+	}{
+		{name: "uncached"},
+		{name: "cached", cacheConfig: cacheConfig},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			_, client := serveGRPC(b, testServer(b, bench.cacheConfig, 500))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				streamChunks(b, client, req)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+	"grpc-vs-http/proto/protoconnect"
+
+	"connectrpc.com/connect"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc/status"
+)
+
+// connectServer serves DataService with connect-go, which speaks the
+// Connect, gRPC and gRPC-Web protocols over plain net/http. Connect unary
+// calls are ordinary POSTs of application/proto or application/json bodies,
+// which separates protobuf encoding from gRPC framing in benchmarks.
+type connectServer struct {
+	server *Server
+}
+
+// GetHotelsStreaming implements the streaming method over Connect
+func (c connectServer) GetHotelsStreaming(ctx context.Context, req *connect.Request[pb.StreamRequest], stream *connect.ServerStream[pb.HotelChunk]) error {
+	observer := c.server.observeStream(ctx, transportConnect)
+	defer observer.done()
+
+	return connectError(grpcError(c.server.snapshot().Stream(req.Msg, observer.wrap(stream.Send))))
+}
+
+// GetHotel implements the unary lookup over Connect
+func (c connectServer) GetHotel(ctx context.Context, req *connect.Request[pb.GetHotelRequest]) (*connect.Response[pb.Hotel], error) {
+	hotel, err := c.server.GetHotel(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(hotel), nil
+}
+
+// GetHotelStats implements the aggregation over Connect
+func (c connectServer) GetHotelStats(ctx context.Context, req *connect.Request[pb.HotelStatsRequest]) (*connect.Response[pb.HotelStats], error) {
+	stats, err := c.server.GetHotelStats(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(stats), nil
+}
+
+// ListHotels implements pagination over Connect
+func (c connectServer) ListHotels(ctx context.Context, req *connect.Request[pb.ListHotelsRequest]) (*connect.Response[pb.ListHotelsResponse], error) {
+	page, err := c.server.ListHotels(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(page), nil
+}
+
+// connectError converts a gRPC status error to a Connect error with the same
+// code and message. Other errors pass through.
+func connectError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	}
+	return err
+}
+
+// serveConnect serves DataService over HTTP/1.1 and cleartext HTTP/2 (h2c)
+// on addr. gRPC-Web requests are handed to grpcWeb instead, which also
+// answers their CORS preflights.
+func serveConnect(addr string, server *Server, grpcWeb *grpcWebHandler) {
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewDataServiceHandler(connectServer{server: server},
+		connect.WithReadMaxBytes(1000*1024*1024), // Same limits as the gRPC server
+		connect.WithSendMaxBytes(1000*1024*1024),
+	))
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           h2c.NewHandler(withGRPCWeb(grpcWeb, metrics.WireHandler(mux)), &http2.Server{MaxConcurrentStreams: 1000}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Connect/HTTP DataService running on %s (HTTP/1.1 and h2c, Connect, gRPC-Web text and binary)", addr)
+	if err := httpServer.ListenAndServe(); err != nil {
+		log.Fatalf("Failed to serve Connect: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+)
+
+// grpcWebHandler serves gRPC-Web calls to the gRPC server, in both the binary
+// (application/grpc-web+proto) and the base64 text (application/grpc-web-text)
+// formats
+type grpcWebHandler struct {
+	wrapped *grpcweb.WrappedGrpcServer
+	cors    *cors.Cors // nil when cross-origin calls are not allowed
+}
+
+// newGRPCWebHandler wraps the gRPC server for gRPC-Web. Cross-origin calls
+// are allowed from the given origins only; without origins, only same-origin
+// calls work. "*" allows any origin, but never with credentials.
+//
+// CORS is handled here rather than by grpcweb, which always allows
+// credentials and so would echo any origin back as trusted.
+func newGRPCWebHandler(s *grpc.Server, corsOrigins []string) *grpcWebHandler {
+	h := &grpcWebHandler{
+		wrapped: grpcweb.WrapServer(s, grpcweb.WithCorsForRegisteredEndpointsOnly(true)),
+	}
+
+	origins := normalizeOrigins(corsOrigins)
+	if len(origins) > 0 {
+		h.cors = cors.New(cors.Options{
+			AllowedOrigins:   origins,
+			AllowedMethods:   []string{http.MethodPost},
+			AllowedHeaders:   []string{"*"},
+			AllowCredentials: false,
+			MaxAge:           int(10 * time.Minute / time.Second), // Keep preflights rare
+		})
+	}
+	return h
+}
+
+// withGRPCWeb routes gRPC-Web requests and, when cross-origin calls are
+// allowed, their CORS preflights to the gRPC server, and everything else to next
+func withGRPCWeb(h *grpcWebHandler, next http.Handler) http.Handler {
+	serve := http.Handler(http.HandlerFunc(h.wrapped.HandleGrpcWebRequest))
+	if h.cors != nil {
+		serve = h.cors.Handler(serve)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.wrapped.IsGrpcWebRequest(r) || (h.cors != nil && h.wrapped.IsAcceptableGrpcCorsRequest(r)) {
+			serve.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// originList is a flag.Value collecting repeated --cors-origin flags, each
+// possibly comma-separated
+type originList []string
+
+// String implements flag.Value
+func (o *originList) String() string {
+	return strings.Join(*o, ",")
+}
+
+// Set implements flag.Value
+func (o *originList) Set(value string) error {
+	if len(normalizeOrigins([]string{value})) == 0 {
+		return errors.New("empty origin")
+	}
+	*o = append(*o, value)
+	return nil
+}
+
+// normalizeOrigins splits comma-separated CORS origins and trims them,
+// dropping empty entries and trailing slashes. Any "*" collapses the list to
+// just "*".
+func normalizeOrigins(corsOrigins []string) []string {
+	var origins []string
+	for _, value := range corsOrigins {
+		for _, origin := range strings.Split(value, ",") {
+			origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+			switch origin {
+			case "":
+				continue
+			case "*":
+				return []string{"*"}
+			}
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
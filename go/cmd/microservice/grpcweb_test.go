@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-vs-http/internal/datagen"
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// startTestServer serves a small generated catalog over native gRPC and
+// gRPC-Web, both on 127.0.0.1:0, and returns a native client and the
+// gRPC-Web base URL
+func startTestServer(t *testing.T, corsOrigins []string) (pb.DataServiceClient, string) {
+	t.Helper()
+
+	s, client := serveGRPC(t, testServer(t, nil, 25))
+	web := httptest.NewServer(withGRPCWeb(newGRPCWebHandler(s, corsOrigins), http.NotFoundHandler()))
+	t.Cleanup(web.Close)
+
+	return client, web.URL
+}
+
+// testServer returns a Server with a generated catalog of the given number
+// of hotels installed
+func testServer(tb testing.TB, cacheConfig *chunkCacheConfig, hotels int) *Server {
+	tb.Helper()
+
+	cfg := datagen.DefaultConfig()
+	cfg.Hotels = hotels
+	cfg.RoomsPerHotel = 3
+	cfg.GeneratedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // Same catalog for every server
+	server := NewServer(cacheConfig, metrics.New())
+	if _, err := server.swap(datagen.Catalog(cfg)); err != nil {
+		tb.Fatal(err)
+	}
+	return server
+}
+
+// serveGRPC serves server over native gRPC on 127.0.0.1:0 and returns the
+// grpc.Server and a client connected to it
+func serveGRPC(tb testing.TB, server *Server) (*grpc.Server, pb.DataServiceClient) {
+	tb.Helper()
+
+	s := grpc.NewServer(grpc.StatsHandler(metrics.ServerWireStats{}))
+	pb.RegisterDataServiceServer(s, server)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	go s.Serve(lis)
+	tb.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+
+	return s, pb.NewDataServiceClient(conn)
+}
+
+// nativeChunks collects the chunks of a native gRPC GetHotelsStreaming call
+func nativeChunks(t *testing.T, client pb.DataServiceClient, req *pb.StreamRequest) []*pb.HotelChunk {
+	t.Helper()
+
+	stream, err := client.GetHotelsStreaming(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*pb.HotelChunk
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks
+		}
+		if err != nil {
+			t.Fatalf("native stream: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+// grpcWebResult is a decoded gRPC-Web response
+type grpcWebResult struct {
+	chunks   []*pb.HotelChunk
+	trailers http.Header
+}
+
+// callGRPCWeb calls GetHotelsStreaming as a gRPC-Web client would, in the
+// binary format or, with text set, the base64 text format
+func callGRPCWeb(t *testing.T, baseURL string, req *pb.StreamRequest, text bool) grpcWebResult {
+	t.Helper()
+
+	msg, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := frame(0, msg)
+	contentType := "application/grpc-web+proto"
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+		contentType = "application/grpc-web-text"
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, baseURL+"/data.DataService/GetHotelsStreaming", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("X-Grpc-Web", "1")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %s", resp.Status)
+	}
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, contentType) {
+		t.Fatalf("response Content-Type = %q, want %q", got, contentType)
+	}
+
+	var r io.Reader = resp.Body
+	if text {
+		r = &base64Parts{r: bufio.NewReader(resp.Body)}
+	}
+
+	result := grpcWebResult{trailers: http.Header{}}
+	for {
+		flag, payload, err := readFrame(r)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+
+		if flag&0x80 != 0 {
+			for _, line := range strings.Split(strings.TrimSpace(string(payload)), "\r\n") {
+				name, value, _ := strings.Cut(line, ":")
+				result.trailers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			}
+			continue
+		}
+
+		chunk := &pb.HotelChunk{}
+		if err := proto.Unmarshal(payload, chunk); err != nil {
+			t.Fatalf("unmarshal chunk: %v", err)
+		}
+		result.chunks = append(result.chunks, chunk)
+	}
+}
+
+// frame prefixes a message with the gRPC length-prefixed message header
+func frame(flag byte, msg []byte) []byte {
+	out := make([]byte, 5, 5+len(msg))
+	out[0] = flag
+	binary.BigEndian.PutUint32(out[1:], uint32(len(msg)))
+	return append(out, msg...)
+}
+
+// readFrame reads one length-prefixed gRPC-Web frame
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, fmt.Errorf("frame payload: %w", err)
+	}
+	return header[0], payload, nil
+}
+
+// base64Parts decodes a gRPC-Web text body, which may be several padded
+// base64 parts back to back. Every 4-character group decodes on its own.
+type base64Parts struct {
+	r       *bufio.Reader
+	pending []byte
+}
+
+func (b *base64Parts) Read(p []byte) (int, error) {
+	for len(b.pending) == 0 {
+		var group [4]byte
+		if _, err := io.ReadFull(b.r, group[:]); err != nil {
+			return 0, err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(string(group[:]))
+		if err != nil {
+			return 0, err
+		}
+		b.pending = decoded
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+func TestGRPCWebMatchesNativeGRPC(t *testing.T) {
+	client, baseURL := startTestServer(t, nil)
+	req := &pb.StreamRequest{ChunkSize: 10}
+	want := nativeChunks(t, client, req)
+	if len(want) != 3 {
+		t.Fatalf("native call returned %d chunks, want 3", len(want))
+	}
+
+	for _, format := range []struct {
+		name string
+		text bool
+	}{
+		{name: "application/grpc-web+proto"},
+		{name: "application/grpc-web-text", text: true},
+	} {
+		t.Run(format.name, func(t *testing.T) {
+			got := callGRPCWeb(t, baseURL, req, format.text)
+
+			if status := got.trailers.Get("grpc-status"); status != "0" {
+				t.Fatalf("grpc-status = %q (message %q), want 0", status, got.trailers.Get("grpc-message"))
+			}
+			if len(got.chunks) != len(want) {
+				t.Fatalf("got %d chunks, want %d", len(got.chunks), len(want))
+			}
+			for i := range want {
+				if !proto.Equal(got.chunks[i], want[i]) {
+					t.Fatalf("chunk %d differs from the native gRPC chunk", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCWebCORS(t *testing.T) {
+	const origin = "https://dashboard.example.com"
+
+	tests := []struct {
+		name        string
+		corsOrigins []string
+		origin      string
+		wantAllow   string
+	}{
+		{name: "no origins configured", origin: origin},
+		{name: "listed origin", corsOrigins: []string{"http://localhost:5173," + origin + "/"}, origin: origin, wantAllow: origin},
+		{name: "unlisted origin", corsOrigins: []string{"http://localhost:5173"}, origin: origin},
+		{name: "wildcard", corsOrigins: []string{"*"}, origin: origin, wantAllow: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, baseURL := startTestServer(t, tt.corsOrigins)
+
+			req, err := http.NewRequest(http.MethodOptions, baseURL+"/data.DataService/GetHotelsStreaming", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllow)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Credentials"); got != "" {
+				t.Fatalf("Access-Control-Allow-Credentials = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestOriginListSet(t *testing.T) {
+	var origins originList
+	for _, value := range []string{"https://a.example.com, https://b.example.com/", "http://localhost:5173"} {
+		if err := origins.Set(value); err != nil {
+			t.Fatalf("Set(%q) error = %v", value, err)
+		}
+	}
+	if err := origins.Set(" , "); err == nil {
+		t.Fatal(`Set(" , ") error = nil, want an error`)
+	}
+
+	got := normalizeOrigins(origins)
+	want := []string{"https://a.example.com", "https://b.example.com", "http://localhost:5173"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("origins = %v, want %v", got, want)
+	}
+}
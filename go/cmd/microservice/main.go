@@ -1,130 +1,215 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
-	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/codec"
+	"grpc-vs-http/internal/compression"
+	"grpc-vs-http/internal/metrics"
+	"grpc-vs-http/internal/tracing"
 	pb "grpc-vs-http/proto"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
-// DataFile represents the structure of the data.json file
-type DataFile struct {
-	Metadata json.RawMessage `json:"metadata"`
-	Hotels   json.RawMessage `json:"hotels"`
-}
-
 // Server implements the gRPC DataService
 type Server struct {
 	pb.UnimplementedDataServiceServer
-	pbHotels   []*pb.Hotel  // Pre-converted protobuf hotels
-	pbMetadata *pb.Metadata // Pre-converted protobuf metadata
+	current atomic.Pointer[catalog.Catalog] // Snapshot handed to new calls
+
+	cacheConfig *chunkCacheConfig // nil disables the chunk cache
+	cache       atomic.Pointer[chunkCache]
+
+	metrics *metrics.Metrics
 }
 
-// NewServer creates a new server instance with loaded data
-func NewServer() *Server {
-	pbHotels, pbMetadata := loadData()
+// Transports of the microservice, as labelled in its stream metrics
+const (
+	transportGRPC    = "grpc" // Includes gRPC-Web, served by the same grpc.Server
+	transportConnect = "connect"
+)
 
-	log.Printf("Loaded %d hotels from data file", len(pbHotels))
+// NewServer creates a new server instance. It serves no data until a
+// catalog is installed with swap. A non-nil cacheConfig pre-marshals the
+// chunks of every installed catalog.
+func NewServer(cacheConfig *chunkCacheConfig, m *metrics.Metrics) *Server {
+	return &Server{cacheConfig: cacheConfig, metrics: m}
+}
 
-	return &Server{
-		pbHotels:   pbHotels,
-		pbMetadata: pbMetadata,
-	}
+// snapshot returns the catalog new calls should be served from. Callers keep
+// using the returned snapshot for the whole call, even if a reload swaps it.
+func (s *Server) snapshot() *catalog.Catalog {
+	return s.current.Load()
 }
 
-// loadData reads and parses the data.json file directly into protobuf types
-func loadData() ([]*pb.Hotel, *pb.Metadata) {
-	// Try multiple possible paths for the data file
-	possiblePaths := []string{
-		"../../../data.json", // When running from cmd/microservice/
-		"../../data.json",    // When running from go/
-		"data.json",          // When running from project root
-		"../data.json",       // Alternative path
-	}
-
-	var dataPath string
-	var file []byte
-	var err error
-
-	for _, path := range possiblePaths {
-		dataPath = filepath.Join(path)
-		file, err = os.ReadFile(dataPath)
-		if err == nil {
-			log.Printf("Found data file at: %s", dataPath)
-			break
+// swap atomically replaces the served catalog and returns the previous one.
+// The chunk cache of the new catalog is built first, so new calls find it.
+func (s *Server) swap(cat *catalog.Catalog) (*catalog.Catalog, error) {
+	if s.cacheConfig != nil {
+		cache, err := newChunkCache(cat, s.cacheConfig)
+		if err != nil {
+			return nil, err
 		}
+		s.cache.Store(cache)
 	}
+	return s.current.Swap(cat), nil
+}
 
+// resolveSource picks the data source from flags and environment
+func resolveSource(flagPaths []string) (catalog.Source, error) {
+	source, err := catalog.Resolve(flagPaths, os.Getenv(catalog.EnvDataPath))
 	if err != nil {
-		log.Fatalf("Failed to read data file from any location: %v", err)
+		return catalog.Source{}, err
 	}
+	log.Printf("Using data source: %s", source)
+
+	return source, nil
+}
 
-	var data DataFile
-	if err := json.Unmarshal(file, &data); err != nil {
-		log.Fatalf("Failed to parse JSON: %v", err)
+// GetHotelsStreaming implements the streaming gRPC method. Unfiltered
+// protobuf calls with a cached chunk size send pre-marshaled chunks.
+func (s *Server) GetHotelsStreaming(req *pb.StreamRequest, stream pb.DataService_GetHotelsStreamingServer) error {
+	observer := s.observeStream(stream.Context(), transportGRPC)
+	defer observer.done()
+
+	cat := s.snapshot()
+	if cache := s.cache.Load(); cache != nil && cache.catalog == cat {
+		if chunks := cache.lookup(stream.Context(), req); chunks != nil {
+			observer.cached()
+			return cache.send(stream, req, chunks, observer)
+		}
 	}
+	return grpcError(cat.Stream(req, observer.wrap(stream.Send)))
+}
+
+// GetHotel implements the unary lookup of a single hotel
+func (s *Server) GetHotel(ctx context.Context, req *pb.GetHotelRequest) (*pb.Hotel, error) {
+	cat := s.snapshot()
 
-	// Parse metadata
-	var metadata pb.Metadata
-	if err := json.Unmarshal(data.Metadata, &metadata); err != nil {
-		log.Fatalf("Failed to parse metadata: %v", err)
+	var hotel *pb.Hotel
+	var found bool
+	var lookup string
+	switch key := req.Key.(type) {
+	case *pb.GetHotelRequest_HotelId:
+		hotel, found = cat.ByHotelID(key.HotelId)
+		lookup = fmt.Sprintf("hotelId %q", key.HotelId)
+	case *pb.GetHotelRequest_GiataId:
+		hotel, found = cat.ByGiataID(key.GiataId)
+		lookup = fmt.Sprintf("giataId %d", key.GiataId)
+	case *pb.GetHotelRequest_HUid:
+		hotel, found = cat.ByHUID(key.HUid)
+		lookup = fmt.Sprintf("hUid %d", key.HUid)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "one of hotelId, giataId or hUid is required")
 	}
 
-	// Parse hotels array
-	var hotels []*pb.Hotel
-	if err := json.Unmarshal(data.Hotels, &hotels); err != nil {
-		log.Fatalf("Failed to parse hotels: %v", err)
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no hotel with %s", lookup)
 	}
+	return hotel, nil
+}
 
-	return hotels, &metadata
+// GetHotelStats implements the aggregation RPC, counting at the source
+// instead of streaming every hotel to the caller
+func (s *Server) GetHotelStats(ctx context.Context, req *pb.HotelStatsRequest) (*pb.HotelStats, error) {
+	return catalog.Aggregate(s.snapshot().Hotels, req.Filter, req.GroupBy), nil
 }
 
-// GetHotelsStreaming implements the streaming gRPC method
-func (s *Server) GetHotelsStreaming(req *pb.StreamRequest, stream pb.DataService_GetHotelsStreamingServer) error {
-	chunkSize := req.ChunkSize
-	if chunkSize <= 0 {
-		chunkSize = 100 // Default chunk size
+// ListHotels implements paginated unary access to the catalog. After a
+// reload, page tokens of the previous catalog fail with FAILED_PRECONDITION.
+func (s *Server) ListHotels(ctx context.Context, req *pb.ListHotelsRequest) (*pb.ListHotelsResponse, error) {
+	page, err := s.snapshot().Page(req)
+	if err != nil {
+		return nil, grpcError(err)
 	}
+	return page, nil
+}
 
-	totalHotels := len(s.pbHotels)
-	totalChunks := (totalHotels + int(chunkSize) - 1) / int(chunkSize) // Ceiling division
+// grpcError maps catalog errors to gRPC status codes. Errors that already
+// carry a status, such as those returned by stream.Send, pass through.
+func grpcError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, catalog.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, catalog.ErrStalePageToken):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
 
-	for i := 0; i < totalHotels; i += int(chunkSize) {
-		end := i + int(chunkSize)
-		if end > totalHotels {
-			end = totalHotels
-		}
+// serveMetrics serves the Prometheus metrics of the microservice on addr
+func serveMetrics(addr string, m *metrics.Metrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
 
-		chunk := &pb.HotelChunk{
-			Hotels:      s.pbHotels[i:end],
-			ChunkIndex:  int32(i / int(chunkSize)),
-			TotalChunks: int32(totalChunks),
-			IsLast:      end == totalHotels,
-		}
+	log.Printf("Prometheus metrics on %s/metrics", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("Failed to serve metrics: %v", err)
+	}
+}
 
-		// Include metadata only in the first chunk
-		if i == 0 {
-			chunk.Metadata = s.pbMetadata
-		}
+func main() {
+	var dataPaths catalog.PathList
+	flag.Var(&dataPaths, "data", "data file or directory of *.json files; repeat or comma-separate to merge several (default: $"+catalog.EnvDataPath+", then ./data.json probes)")
+	watchInterval := flag.Duration("watch", 0, "poll the data files at this interval and reload them on change (0 disables; SIGHUP always reloads)")
+	connectAddr := flag.String("connect", ":50052", "address to serve DataService over Connect/HTTP and gRPC-Web on (empty disables)")
+	var corsOrigins originList
+	flag.Var(&corsOrigins, "cors-origin", "origin allowed to make cross-origin gRPC-Web calls from a browser, without credentials; repeat or comma-separate, * allows any (default: none)")
+	chunkCacheSizes := flag.String("chunk-cache", "", "comma-separated chunk sizes to pre-marshal unfiltered GetHotelsStreaming chunks for (empty disables)")
+	chunkCacheCompression := flag.String("chunk-cache-compression", "", "comma-separated compressors to also keep compressed copies of cached chunks for, e.g. gzip,zstd")
+	metricsAddr := flag.String("metrics", ":9090", "address to serve Prometheus metrics on, at /metrics (empty disables)")
+	traceExporter := flag.String("trace", "", "export OpenTelemetry spans to otlp (OTEL_EXPORTER_OTLP_* variables), otlp://host:port, stdout or a file path (empty disables)")
+	flag.Parse()
 
-		if err := stream.Send(chunk); err != nil {
-			return err
+	if *traceExporter != "" {
+		shutdown, err := tracing.Setup("microservice", *traceExporter)
+		if err != nil {
+			log.Fatalf("Failed to set up tracing: %v", err)
 		}
+		log.Printf("Tracing to %s", *traceExporter)
+		go tracing.FlushOnSignal(shutdown)
 	}
 
-	return nil
-}
+	cacheConfig, err := parseChunkCacheConfig(*chunkCacheSizes, *chunkCacheCompression)
+	if err != nil {
+		log.Fatalf("Invalid chunk cache: %v", err)
+	}
 
-func main() {
 	// Create server with loaded data
-	server := NewServer()
+	source, err := resolveSource(dataPaths)
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+	m := metrics.New()
+	server := NewServer(cacheConfig, m)
+	reloader := NewReloader(source, server)
+	if err := reloader.Reload(); err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+
+	go reloader.HandleSignals()
+	if *watchInterval > 0 {
+		log.Printf("Watching data files for changes every %s", *watchInterval)
+		go reloader.Watch(*watchInterval)
+	}
 
 	// Start gRPC server with optimized settings
 	lis, err := net.Listen("tcp", ":50051")
@@ -133,8 +218,8 @@ func main() {
 	}
 
 	// Optimized server options
-	// Note: gzip compression is automatically supported on server side
-	// when the encoding/gzip package is imported
+	// Note: responses are compressed with whichever compressor the client
+	// used, among those registered by internal/compression
 	kaep := keepalive.EnforcementPolicy{
 		MinTime:             5 * time.Second,
 		PermitWithoutStream: true,
@@ -147,19 +232,36 @@ func main() {
 		Timeout:               20 * time.Second,
 	}
 
+	grpcMetrics := m.GRPCServer()
 	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor()),
+		grpc.StatsHandler(metrics.ServerWireStats{}), // Sent bytes of each stream
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
 		grpc.MaxRecvMsgSize(1000 * 1024 * 1024), // 100MB
 		grpc.MaxSendMsgSize(1000 * 1024 * 1024), // 100MB
 		grpc.MaxConcurrentStreams(1000),         // Allow up to 1000 concurrent streams
 	}
+	if *traceExporter != "" {
+		// Continues the trace of the caller, carried in the call's metadata
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
 
 	s := grpc.NewServer(opts...)
 	pb.RegisterDataServiceServer(s, server)
+	grpcMetrics.InitializeMetrics(s) // Report every method, even before its first call
+
+	if *connectAddr != "" {
+		go serveConnect(*connectAddr, server, newGRPCWebHandler(s, corsOrigins))
+	}
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr, m)
+	}
 
 	log.Println("gRPC microservice running on port 50051 with optimizations")
-	log.Println("Compression: gzip enabled (automatic server-side support)")
+	log.Printf("Compression: %s, as requested by the client", strings.Join(compression.Names[1:], ", "))
+	log.Printf("Codecs: %s, by content-subtype", strings.Join(codec.Names, ", "))
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// chunkObserver counts the chunks of one stream into the metrics and, when
+// the call is traced, records each of them as an event of its span. Chunk
+// sizes are the bytes the transport wrote for them, as counted into the
+// call's wire counter.
+type chunkObserver struct {
+	metered *metrics.Stream
+	span    trace.Span
+	wire    *metrics.WireCounter
+	counted int64 // wire.Bytes() at the previous chunk
+	chunks  int
+	hotels  int
+}
+
+// observeStream starts observing a stream served over transport. The span
+// is the one the tracing stats handler started for the call, if any, the
+// wire counter the one ServerWireStats or WireHandler gave it.
+func (s *Server) observeStream(ctx context.Context, transport string) *chunkObserver {
+	wire := metrics.WireCounterFrom(ctx)
+	return &chunkObserver{
+		metered: s.metrics.StreamStarted(transport),
+		span:    trace.SpanFromContext(ctx),
+		wire:    wire,
+		counted: wire.Bytes(),
+	}
+}
+
+// wrap returns a send function that observes the chunks send sends
+func (o *chunkObserver) wrap(send func(*pb.HotelChunk) error) func(*pb.HotelChunk) error {
+	return func(chunk *pb.HotelChunk) error {
+		if err := send(chunk); err != nil {
+			return err
+		}
+		o.sent(len(chunk.Hotels))
+		return nil
+	}
+}
+
+// cached marks the stream as served from the chunk cache
+func (o *chunkObserver) cached() {
+	o.span.SetAttributes(attribute.Bool("hotels.cached", true))
+}
+
+// sent records one chunk of the given number of hotels, just sent
+func (o *chunkObserver) sent(hotels int) {
+	bytes := int(o.wire.Bytes() - o.counted)
+	o.counted = o.wire.Bytes()
+
+	o.metered.Chunk(hotels, bytes)
+	if o.span.IsRecording() {
+		o.span.AddEvent("chunk", trace.WithAttributes(
+			attribute.Int("chunk.index", o.chunks),
+			attribute.Int("chunk.hotels", hotels),
+			attribute.Int("chunk.bytes", bytes),
+		))
+	}
+	o.chunks++
+	o.hotels += hotels
+}
+
+// done marks the stream as finished and totals it on the span
+func (o *chunkObserver) done() {
+	o.metered.Done()
+	o.span.SetAttributes(
+		attribute.Int("hotels.chunks", o.chunks),
+		attribute.Int("hotels.count", o.hotels),
+	)
+}
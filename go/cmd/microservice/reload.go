@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"grpc-vs-http/internal/catalog"
+)
+
+// Reloader re-reads the data source and swaps the server's catalog. Parsing
+// happens on the reloading goroutine, so in-flight streams keep sending the
+// snapshot they started with and only new calls observe the new catalog.
+type Reloader struct {
+	source catalog.Source
+	server *Server
+
+	mu          sync.Mutex // Serializes loads
+	fingerprint string     // Fingerprint of the files behind the last load attempt
+}
+
+// NewReloader creates a reloader feeding the given server from source
+func NewReloader(source catalog.Source, server *Server) *Reloader {
+	return &Reloader{source: source, server: server}
+}
+
+// Reload parses the data source and atomically swaps the server's catalog.
+// On failure the current catalog stays in place.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Record the fingerprint before reading, so a file that is still being
+	// written when we parse it is picked up again on the next poll.
+	fingerprint, err := r.currentFingerprint()
+	if err != nil {
+		return err
+	}
+	r.fingerprint = fingerprint
+
+	start := time.Now()
+	cat, err := r.source.Load()
+	if err != nil {
+		return err
+	}
+
+	previous, err := r.server.swap(cat)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		log.Printf("Loaded %d hotels from %d data file(s) in %s",
+			len(cat.Hotels), len(cat.Files), time.Since(start).Round(time.Millisecond))
+	} else {
+		log.Printf("Reloaded %d hotels from %d data file(s) in %s (previously %d hotels)",
+			len(cat.Hotels), len(cat.Files), time.Since(start).Round(time.Millisecond), len(previous.Hotels))
+	}
+	return nil
+}
+
+// Watch polls the data files and reloads when their size or modification
+// time changes. Polling rather than inotify keeps it working on mounted
+// volumes where files are replaced through symlink swaps.
+func (r *Reloader) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		fingerprint, err := r.currentFingerprint()
+		if err != nil {
+			log.Printf("Data watch: %v", err)
+			continue
+		}
+
+		r.mu.Lock()
+		changed := fingerprint != r.fingerprint
+		r.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		log.Println("Data files changed, reloading")
+		if err := r.Reload(); err != nil {
+			log.Printf("Reload failed, still serving previous data: %v", err)
+		}
+	}
+}
+
+// HandleSignals reloads the catalog every time the process receives SIGHUP
+func (r *Reloader) HandleSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		log.Println("Received SIGHUP, reloading data")
+		if err := r.Reload(); err != nil {
+			log.Printf("Reload failed, still serving previous data: %v", err)
+		}
+	}
+}
+
+// currentFingerprint summarizes the name, size and modification time of
+// every file currently behind the source
+func (r *Reloader) currentFingerprint() (string, error) {
+	files, err := r.source.Files()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"grpc-vs-http/internal/catalog"
+	"grpc-vs-http/internal/metrics"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/grpc"
+)
+
+// writeDataFile writes a minimal data file holding the given hotel ids
+func writeDataFile(t *testing.T, path string, ids ...string) {
+	t.Helper()
+
+	hotels := make([]string, len(ids))
+	for i, id := range ids {
+		hotels[i] = fmt.Sprintf(`{"hotelId":%q}`, id)
+	}
+	body := fmt.Sprintf(`{"metadata":{"totalHotels":%d},"hotels":[%s]}`, len(ids), strings.Join(hotels, ","))
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recordingStream is a GetHotelsStreaming server stream that records the
+// hotel ids it is sent. With block set, the first Send signals on sending
+// and waits for release, holding the call in flight.
+type recordingStream struct {
+	grpc.ServerStream
+	ids     []string
+	block   bool
+	sending chan struct{}
+	release chan struct{}
+}
+
+func newRecordingStream(block bool) *recordingStream {
+	return &recordingStream{block: block, sending: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (s *recordingStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *recordingStream) Send(chunk *pb.HotelChunk) error {
+	for _, h := range chunk.Hotels {
+		s.ids = append(s.ids, h.GetHotelId())
+	}
+	if s.block && len(s.ids) == len(chunk.Hotels) {
+		close(s.sending)
+		<-s.release
+	}
+	return nil
+}
+
+// streamIDs serves a whole call with one hotel per chunk and returns the
+// hotel ids it sent
+func streamIDs(t *testing.T, server *Server) []string {
+	t.Helper()
+
+	stream := newRecordingStream(false)
+	if err := server.GetHotelsStreaming(&pb.StreamRequest{ChunkSize: 1}, stream); err != nil {
+		t.Fatal(err)
+	}
+	return stream.ids
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	writeDataFile(t, path, "a1", "a2", "a3")
+
+	server := NewServer(nil, metrics.New())
+	reloader := NewReloader(catalog.Source{Paths: []string{path}, Origin: "flag"}, server)
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("initial Reload() error = %v", err)
+	}
+
+	// A call in flight during the reload keeps sending its snapshot
+	inFlight := newRecordingStream(true)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.GetHotelsStreaming(&pb.StreamRequest{ChunkSize: 1}, inFlight)
+	}()
+	<-inFlight.sending
+
+	writeDataFile(t, path, "b1", "b2")
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	close(inFlight.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a1", "a2", "a3"}; !reflect.DeepEqual(inFlight.ids, want) {
+		t.Fatalf("in-flight call sent %v, want the old catalog %v", inFlight.ids, want)
+	}
+
+	// Calls started after the reload see the new catalog
+	want := []string{"b1", "b2"}
+	if got := streamIDs(t, server); !reflect.DeepEqual(got, want) {
+		t.Fatalf("call after reload sent %v, want %v", got, want)
+	}
+	if _, err := server.GetHotel(context.Background(), &pb.GetHotelRequest{Key: &pb.GetHotelRequest_HotelId{HotelId: "a1"}}); err == nil {
+		t.Fatal("GetHotel(a1) after reload: found a hotel of the old catalog")
+	}
+
+	// A file that fails to parse leaves the previous catalog serving
+	if err := os.WriteFile(path, []byte(`{"hotels":[{"hotelId":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Fatal("Reload() of a truncated file error = nil, want an error")
+	}
+	if got := streamIDs(t, server); !reflect.DeepEqual(got, want) {
+		t.Fatalf("call after failed reload sent %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	pb "grpc-vs-http/proto"
+)
+
+// DataFile represents the structure of the data.json file
+type DataFile struct {
+	Metadata json.RawMessage `json:"metadata"`
+	Hotels   json.RawMessage `json:"hotels"`
+}
+
+// Catalog is a parsed hotel dataset, ready to be served as protobuf.
+// A catalog is never modified once built, so it can be shared between calls.
+type Catalog struct {
+	Hotels   []*pb.Hotel  // Pre-converted protobuf hotels
+	Metadata *pb.Metadata // Pre-converted protobuf metadata
+	Files    []string     // Data files the catalog was built from
+
+	// Generation identifies this build of the catalog. It changes on every
+	// load, so page tokens from before a reload can be detected.
+	Generation uint64
+
+	byHotelID map[string]*pb.Hotel
+	byGiataID map[int32]*pb.Hotel
+	byHUID    map[int32]*pb.Hotel
+}
+
+// New builds a catalog and its lookup indexes
+func New(hotels []*pb.Hotel, metadata *pb.Metadata, files ...string) *Catalog {
+	c := &Catalog{
+		Hotels:     hotels,
+		Metadata:   metadata,
+		Files:      files,
+		Generation: nextGeneration(),
+		byHotelID:  make(map[string]*pb.Hotel, len(hotels)),
+		byGiataID:  make(map[int32]*pb.Hotel, len(hotels)),
+		byHUID:     make(map[int32]*pb.Hotel, len(hotels)),
+	}
+
+	// Primary ids first, so an alternate id never shadows another hotel's primary id
+	for _, h := range hotels {
+		if h.HotelId != nil {
+			addIndex(c.byHotelID, *h.HotelId, h)
+		}
+		if h.GiataId != nil {
+			addIndex(c.byGiataID, *h.GiataId, h)
+		}
+		if h.HUid != nil {
+			addIndex(c.byHUID, *h.HUid, h)
+		}
+	}
+	for _, h := range hotels {
+		for _, id := range h.HotelIds {
+			addIndex(c.byHotelID, id, h)
+		}
+	}
+
+	return c
+}
+
+// lastGeneration holds the generation of the most recently built catalog
+var lastGeneration atomic.Uint64
+
+// nextGeneration returns a generation that is unique within the process and,
+// being seeded from the clock, unlikely to repeat across restarts
+func nextGeneration() uint64 {
+	now := uint64(time.Now().UnixNano())
+	for {
+		last := lastGeneration.Load()
+		next := now
+		if next <= last {
+			next = last + 1
+		}
+		if lastGeneration.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// addIndex keeps the first hotel seen for a key, so merged files resolve
+// duplicates in load order
+func addIndex[K comparable](index map[K]*pb.Hotel, key K, h *pb.Hotel) {
+	if _, exists := index[key]; !exists {
+		index[key] = h
+	}
+}
+
+// ByHotelID returns the hotel with the given hotelId (or alternate id in hotelIds)
+func (c *Catalog) ByHotelID(id string) (*pb.Hotel, bool) {
+	h, ok := c.byHotelID[id]
+	return h, ok
+}
+
+// ByGiataID returns the hotel with the given giataId
+func (c *Catalog) ByGiataID(id int32) (*pb.Hotel, bool) {
+	h, ok := c.byGiataID[id]
+	return h, ok
+}
+
+// ByHUID returns the hotel with the given hUid
+func (c *Catalog) ByHUID(id int32) (*pb.Hotel, bool) {
+	h, ok := c.byHUID[id]
+	return h, ok
+}
+
+// LoadFile reads and parses a single data file directly into protobuf types
+func LoadFile(path string) (*Catalog, error) {
+	hotels, metadata, err := parseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(hotels, metadata, path), nil
+}
+
+// parseFile reads and parses a single data file, leaving the indexes to the
+// catalog it ends up in
+func parseFile(path string) ([]*pb.Hotel, *pb.Metadata, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read data file: %w", err)
+	}
+
+	var data DataFile
+	if err := json.Unmarshal(file, &data); err != nil {
+		return nil, nil, fmt.Errorf("parse JSON in %s: %w", path, err)
+	}
+
+	// Parse metadata
+	var metadata pb.Metadata
+	if len(data.Metadata) > 0 {
+		if err := json.Unmarshal(data.Metadata, &metadata); err != nil {
+			return nil, nil, fmt.Errorf("parse metadata in %s: %w", path, err)
+		}
+	}
+
+	// Parse hotels array
+	if len(data.Hotels) == 0 {
+		return nil, nil, fmt.Errorf("parse hotels in %s: missing \"hotels\" array", path)
+	}
+	var hotels []*pb.Hotel
+	if err := json.Unmarshal(data.Hotels, &hotels); err != nil {
+		return nil, nil, fmt.Errorf("parse hotels in %s: %w", path, err)
+	}
+
+	return hotels, &metadata, nil
+}
+
+// Load parses every file and merges them into one catalog, in order. The
+// indexes are built once, for the merged catalog.
+func Load(files ...string) (*Catalog, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no data files to load")
+	}
+
+	var hotels []*pb.Hotel
+	metadata := make([]*pb.Metadata, 0, len(files))
+	for _, path := range files {
+		fileHotels, fileMetadata, err := parseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		hotels = append(hotels, fileHotels...)
+		metadata = append(metadata, fileMetadata)
+	}
+
+	return New(hotels, mergeMetadata(metadata), files...), nil
+}
+
+// Merge concatenates the hotels of several catalogs. Counters in the metadata
+// are summed, descriptive fields are taken from the first catalog that sets them.
+func Merge(parts ...*Catalog) *Catalog {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	var hotels []*pb.Hotel
+	var files []string
+	metadata := make([]*pb.Metadata, 0, len(parts))
+	for _, part := range parts {
+		hotels = append(hotels, part.Hotels...)
+		files = append(files, part.Files...)
+		metadata = append(metadata, part.Metadata)
+	}
+
+	return New(hotels, mergeMetadata(metadata), files...)
+}
+
+// mergeMetadata sums the counters of several files' metadata and takes the
+// descriptive fields from the first that sets them. A single file's metadata
+// is kept as is.
+func mergeMetadata(parts []*pb.Metadata) *pb.Metadata {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	metadata := &pb.Metadata{}
+	for _, md := range parts {
+		if md == nil {
+			continue
+		}
+		if metadata.GeneratedAt == "" {
+			metadata.GeneratedAt = md.GeneratedAt
+		}
+		if metadata.GeneratedBy == "" {
+			metadata.GeneratedBy = md.GeneratedBy
+		}
+		metadata.TotalHotels += md.TotalHotels
+		metadata.ActualHotels += md.ActualHotels
+		metadata.ActualSizeMB += md.ActualSizeMB
+	}
+	return metadata
+}
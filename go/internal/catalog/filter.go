@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"strings"
+
+	pb "grpc-vs-http/proto"
+)
+
+// IsEmptyFilter reports whether f matches every hotel
+func IsEmptyFilter(f *pb.HotelFilter) bool {
+	return f == nil ||
+		len(f.CountryCodes) == 0 && len(f.CityIds) == 0 &&
+			f.MinRating == nil && f.MaxRating == nil &&
+			f.MinPrice == nil && f.MaxPrice == nil &&
+			f.Available == nil && len(f.Boards) == 0 && len(f.Tags) == 0
+}
+
+// Filter returns the hotels matching f, in catalog order. An empty filter
+// returns hotels itself without copying.
+func Filter(hotels []*pb.Hotel, f *pb.HotelFilter) []*pb.Hotel {
+	if IsEmptyFilter(f) {
+		return hotels
+	}
+
+	var matched []*pb.Hotel
+	for _, h := range hotels {
+		if Match(h, f) {
+			matched = append(matched, h)
+		}
+	}
+	return matched
+}
+
+// Match reports whether a hotel satisfies every condition of f. A hotel
+// lacking a field that the filter constrains never matches.
+func Match(h *pb.Hotel, f *pb.HotelFilter) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.CountryCodes) > 0 && (h.CountryCode == nil || !containsFold(f.CountryCodes, *h.CountryCode)) {
+		return false
+	}
+	if len(f.CityIds) > 0 && (h.CityId == nil || !contains(f.CityIds, *h.CityId)) {
+		return false
+	}
+	if f.MinRating != nil && (h.Rating == nil || *h.Rating < *f.MinRating) {
+		return false
+	}
+	if f.MaxRating != nil && (h.Rating == nil || *h.Rating > *f.MaxRating) {
+		return false
+	}
+	// Price bounds keep hotels whose [minRate, maxRate] range overlaps [minPrice, maxPrice]
+	if f.MinPrice != nil && (h.MaxRate == nil || *h.MaxRate < *f.MinPrice) {
+		return false
+	}
+	if f.MaxPrice != nil && (h.MinRate == nil || *h.MinRate > *f.MaxPrice) {
+		return false
+	}
+	if f.Available != nil && (h.Available == nil || *h.Available != *f.Available) {
+		return false
+	}
+	if len(f.Boards) > 0 && !containsAnyFold(f.Boards, h.Boards) {
+		return false
+	}
+	if len(f.Tags) > 0 && (h.Tag == nil || !containsFold(f.Tags, *h.Tag)) {
+		return false
+	}
+
+	return true
+}
+
+func contains[T comparable](values []T, v T) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyFold(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if containsFold(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
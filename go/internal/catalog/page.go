@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// pageTokenVersion is bumped whenever the token layout changes
+const pageTokenVersion = 1
+
+// ErrInvalidPageToken is returned for tokens that cannot be decoded
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageToken is the decoded form of the opaque ListHotels page token. It pins
+// the catalog generation and query it was issued for, so a token is never
+// silently applied to a reloaded catalog or to a different filter.
+type PageToken struct {
+	Generation uint64 // Catalog.Generation the offset refers to
+	Offset     int    // Index of the first hotel of the page in the filtered list
+	Query      uint64 // QueryHash of the request parameters
+}
+
+// Encode serializes the token as URL-safe base64
+func (t PageToken) Encode() string {
+	buf := []byte{pageTokenVersion}
+	buf = binary.AppendUvarint(buf, t.Generation)
+	buf = binary.AppendUvarint(buf, uint64(t.Offset))
+	buf = binary.AppendUvarint(buf, t.Query)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodePageToken parses a token produced by Encode
+func DecodePageToken(s string) (PageToken, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) == 0 || buf[0] != pageTokenVersion {
+		return PageToken{}, ErrInvalidPageToken
+	}
+	buf = buf[1:]
+
+	var fields [3]uint64
+	for i := range fields {
+		v, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return PageToken{}, ErrInvalidPageToken
+		}
+		fields[i] = v
+		buf = buf[n:]
+	}
+	if len(buf) != 0 || fields[1] > uint64(int(^uint(0)>>1)) {
+		return PageToken{}, ErrInvalidPageToken
+	}
+
+	return PageToken{Generation: fields[0], Offset: int(fields[1]), Query: fields[2]}, nil
+}
+
+// QueryHash fingerprints the request parameters a page token is bound to
+func QueryHash(msgs ...proto.Message) uint64 {
+	h := fnv.New64a()
+	for _, m := range msgs {
+		b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+		h.Write(b)
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Projection copies a selected subset of Hotel fields, as described by
+// field mask paths such as "available" or "rooms.rates.amount"
+type Projection struct {
+	root *projectionNode
+}
+
+// projectionNode lists the fields kept at one level of the message tree. A
+// field without children is copied whole.
+type projectionNode struct {
+	fields   []protoreflect.FieldDescriptor
+	children map[protoreflect.FieldNumber]*projectionNode
+}
+
+// NewProjection validates the paths against the Hotel message. It returns
+// nil, meaning "keep everything", when paths is empty.
+func NewProjection(paths []string) (*Projection, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	root := &projectionNode{}
+	hotel := (&pb.Hotel{}).ProtoReflect().Descriptor()
+	for _, path := range paths {
+		if err := root.add(hotel, path); err != nil {
+			return nil, err
+		}
+	}
+	return &Projection{root: root}, nil
+}
+
+func (n *projectionNode) add(md protoreflect.MessageDescriptor, path string) error {
+	node := n
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		fd := md.Fields().ByName(protoreflect.Name(segment))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(segment)
+		}
+		if fd == nil {
+			return fmt.Errorf("invalid field path %q: %s has no field %q", path, md.Name(), segment)
+		}
+
+		child, seen := node.children[fd.Number()]
+		if seen && child == nil {
+			return nil // A parent path already keeps the whole field
+		}
+		if !seen {
+			node.fields = append(node.fields, fd)
+			if node.children == nil {
+				node.children = make(map[protoreflect.FieldNumber]*projectionNode)
+			}
+		}
+
+		last := i == len(segments)-1
+		if last {
+			node.children[fd.Number()] = nil // Keep the whole field, dropping narrower paths
+			return nil
+		}
+		if fd.Message() == nil || fd.IsMap() {
+			return fmt.Errorf("invalid field path %q: %s is not a message", path, segment)
+		}
+		if child == nil {
+			child = &projectionNode{}
+			node.children[fd.Number()] = child
+		}
+		node, md = child, fd.Message()
+	}
+	return nil
+}
+
+// Hotel returns a pruned copy of h. Kept fields share memory with h, so the
+// copy must be treated as read-only, like the catalog itself.
+func (p *Projection) Hotel(h *pb.Hotel) *pb.Hotel {
+	dst := &pb.Hotel{}
+	p.root.project(h.ProtoReflect(), dst.ProtoReflect())
+	return dst
+}
+
+// Hotels projects every hotel of a slice
+func (p *Projection) Hotels(hotels []*pb.Hotel) []*pb.Hotel {
+	projected := make([]*pb.Hotel, len(hotels))
+	for i, h := range hotels {
+		projected[i] = p.Hotel(h)
+	}
+	return projected
+}
+
+func (n *projectionNode) project(src, dst protoreflect.Message) {
+	for _, fd := range n.fields {
+		if !src.Has(fd) {
+			continue
+		}
+
+		child := n.children[fd.Number()]
+		if child == nil {
+			dst.Set(fd, src.Get(fd))
+			continue
+		}
+
+		if fd.IsList() {
+			srcList := src.Get(fd).List()
+			dstList := dst.Mutable(fd).List()
+			for i := 0; i < srcList.Len(); i++ {
+				element := dstList.NewElement()
+				child.project(srcList.Get(i).Message(), element.Message())
+				dstList.Append(element)
+			}
+			continue
+		}
+		child.project(src.Get(fd).Message(), dst.Mutable(fd).Message())
+	}
+}
@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"errors"
+
+	pb "grpc-vs-http/proto"
+)
+
+// DefaultChunkSize is used when a StreamRequest does not set a chunk size
+const DefaultChunkSize = 100
+
+// Page size bounds for ListHotels
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+var (
+	// ErrInvalidRequest wraps errors caused by malformed request parameters
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrStalePageToken is returned for page tokens issued before a reload
+	ErrStalePageToken = errors.New("page token refers to a catalog that has since been reloaded; restart from the first page")
+)
+
+// requestError marks err as caused by the request. It matches
+// ErrInvalidRequest with errors.Is but keeps err's message unchanged.
+type requestError struct{ err error }
+
+func (e requestError) Error() string        { return e.err.Error() }
+func (e requestError) Unwrap() error        { return e.err }
+func (e requestError) Is(target error) bool { return target == ErrInvalidRequest }
+
+func invalidRequest(err error) error {
+	return requestError{err}
+}
+
+// Stream splits the hotels matching req into chunks and passes them to send,
+// in order. Filtering happens before chunking so only matching hotels are
+// sent; the dataset metadata is only included in the first chunk. It stops at
+// the first error returned by send.
+func (c *Catalog) Stream(req *pb.StreamRequest, send func(*pb.HotelChunk) error) error {
+	projection, err := NewProjection(req.GetFields().GetPaths())
+	if err != nil {
+		return invalidRequest(err)
+	}
+
+	chunkSize := int(req.GetChunkSize())
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	hotels := Filter(c.Hotels, req.GetFilter())
+
+	totalHotels := len(hotels)
+	totalChunks := (totalHotels + chunkSize - 1) / chunkSize // Ceiling division
+
+	for i := 0; i < totalHotels; i += chunkSize {
+		end := min(i+chunkSize, totalHotels)
+
+		chunkHotels := hotels[i:end]
+		if projection != nil {
+			chunkHotels = projection.Hotels(chunkHotels)
+		}
+
+		chunk := &pb.HotelChunk{
+			Hotels:      chunkHotels,
+			ChunkIndex:  int32(i / chunkSize),
+			TotalChunks: int32(totalChunks),
+			IsLast:      end == totalHotels,
+		}
+
+		// Include metadata only in the first chunk
+		if i == 0 {
+			chunk.Metadata = c.Metadata
+		}
+
+		if err := send(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Page returns one page of the hotels matching req. Page tokens are bound to
+// the catalog generation, so after a reload they fail with ErrStalePageToken
+// instead of returning pages of a different dataset.
+func (c *Catalog) Page(req *pb.ListHotelsRequest) (*pb.ListHotelsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	projection, err := NewProjection(req.GetFields().GetPaths())
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	query := QueryHash(req.GetFilter(), req.GetFields())
+	offset := 0
+	if req.GetPageToken() != "" {
+		token, err := DecodePageToken(req.GetPageToken())
+		if err != nil {
+			return nil, invalidRequest(err)
+		}
+		if token.Query != query {
+			return nil, invalidRequest(errors.New("page token was issued for a different filter or field mask"))
+		}
+		if token.Generation != c.Generation {
+			return nil, ErrStalePageToken
+		}
+		offset = token.Offset
+	}
+
+	hotels := Filter(c.Hotels, req.GetFilter())
+	if offset > len(hotels) {
+		return nil, invalidRequest(ErrInvalidPageToken)
+	}
+	end := min(offset+pageSize, len(hotels))
+
+	page := hotels[offset:end]
+	if projection != nil {
+		page = projection.Hotels(page)
+	}
+
+	resp := &pb.ListHotelsResponse{
+		Hotels:    page,
+		TotalSize: int32(len(hotels)),
+	}
+	if end < len(hotels) {
+		resp.NextPageToken = PageToken{Generation: c.Generation, Offset: end, Query: query}.Encode()
+	}
+	// Include metadata only in the first page
+	if offset == 0 {
+		resp.Metadata = c.Metadata
+	}
+	return resp, nil
+}
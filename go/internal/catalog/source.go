@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// EnvDataPath is the environment variable consulted when no --data flag is given
+const EnvDataPath = "DATA_PATH"
+
+// DefaultPaths are probed in order when neither --data nor DATA_PATH is set
+var DefaultPaths = []string{
+	"../../../data.json", // When running from cmd/microservice/
+	"../../data.json",    // When running from go/
+	"data.json",          // When running from project root
+	"../data.json",       // Alternative path
+}
+
+// Source describes where a catalog is loaded from
+type Source struct {
+	Paths  []string // Files or directories of *.json files
+	Origin string   // How the paths were chosen: "flag", "env" or "default"
+}
+
+// Resolve picks the data source. Explicit flag values win over DATA_PATH,
+// which wins over probing DefaultPaths. Explicit paths are never replaced by
+// a fallback: if they do not exist, Resolve fails.
+func Resolve(flagPaths []string, env string) (Source, error) {
+	if paths := splitPaths(flagPaths...); len(paths) > 0 {
+		return checkSource(Source{Paths: paths, Origin: "flag"})
+	}
+
+	if paths := splitPaths(env); len(paths) > 0 {
+		return checkSource(Source{Paths: paths, Origin: "env"})
+	}
+
+	for _, path := range DefaultPaths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return Source{Paths: []string{path}, Origin: "default"}, nil
+		}
+	}
+
+	return Source{}, fmt.Errorf("no data file found (tried %s); set --data or %s",
+		strings.Join(DefaultPaths, ", "), EnvDataPath)
+}
+
+// Files expands the source into the list of data files to load. Directories
+// contribute their *.json files in lexical order.
+func (s Source) Files() ([]string, error) {
+	var files []string
+	for _, path := range s.Paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("data path %s (from %s): %w", path, s.Origin, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("data directory %s (from %s) contains no *.json files", path, s.Origin)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// Load reads every file of the source into a single catalog
+func (s Source) Load() (*Catalog, error) {
+	files, err := s.Files()
+	if err != nil {
+		return nil, err
+	}
+	return Load(files...)
+}
+
+// String describes the source for log messages
+func (s Source) String() string {
+	return fmt.Sprintf("%s (from %s)", strings.Join(s.Paths, ", "), s.Origin)
+}
+
+func checkSource(s Source) (Source, error) {
+	if _, err := s.Files(); err != nil {
+		return Source{}, err
+	}
+	return s, nil
+}
+
+// splitPaths flattens values that may each hold several comma or
+// os.PathListSeparator separated paths, dropping empty entries
+func splitPaths(values ...string) []string {
+	var paths []string
+	for _, value := range values {
+		fields := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == os.PathListSeparator
+		})
+		for _, field := range fields {
+			if field = strings.TrimSpace(field); field != "" {
+				paths = append(paths, field)
+			}
+		}
+	}
+	return paths
+}
+
+// PathList is a flag.Value collecting repeated --data flags
+type PathList []string
+
+// String implements flag.Value
+func (p *PathList) String() string {
+	return strings.Join(*p, ",")
+}
+
+// Set implements flag.Value
+func (p *PathList) Set(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("empty data path")
+	}
+	*p = append(*p, value)
+	return nil
+}
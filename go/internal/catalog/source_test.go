@@ -0,0 +1,237 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeDataFile writes a minimal data file holding the given hotel ids
+func writeDataFile(t *testing.T, path string, ids ...string) {
+	t.Helper()
+
+	hotels := make([]string, len(ids))
+	for i, id := range ids {
+		hotels[i] = fmt.Sprintf(`{"hotelId":%q}`, id)
+	}
+	body := fmt.Sprintf(`{"metadata":{"totalHotels":%d},"hotels":[%s]}`, len(ids), strings.Join(hotels, ","))
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	flagFile := filepath.Join(dir, "flag.json")
+	envFile := filepath.Join(dir, "env.json")
+	defaultFile := filepath.Join(dir, "default.json")
+	for _, path := range []string{flagFile, envFile, defaultFile} {
+		writeDataFile(t, path, "h1")
+	}
+	missing := filepath.Join(dir, "missing.json")
+
+	emptyDir := filepath.Join(dir, "empty")
+	if err := os.Mkdir(emptyDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := DefaultPaths
+	t.Cleanup(func() { DefaultPaths = saved })
+
+	tests := []struct {
+		name     string
+		flags    []string
+		env      string
+		defaults []string
+		want     Source
+		wantErr  string
+	}{
+		{
+			name:     "flag beats env and defaults",
+			flags:    []string{flagFile},
+			env:      envFile,
+			defaults: []string{defaultFile},
+			want:     Source{Paths: []string{flagFile}, Origin: "flag"},
+		},
+		{
+			name:     "env beats defaults",
+			env:      envFile,
+			defaults: []string{defaultFile},
+			want:     Source{Paths: []string{envFile}, Origin: "env"},
+		},
+		{
+			name:     "defaults probed in order",
+			defaults: []string{missing, defaultFile},
+			want:     Source{Paths: []string{defaultFile}, Origin: "default"},
+		},
+		{
+			name:     "missing flag path does not fall back",
+			flags:    []string{missing},
+			env:      envFile,
+			defaults: []string{defaultFile},
+			wantErr:  "from flag",
+		},
+		{
+			name:     "missing env path does not fall back",
+			env:      missing,
+			defaults: []string{defaultFile},
+			wantErr:  "from env",
+		},
+		{
+			name:     "no source found",
+			defaults: []string{missing},
+			wantErr:  "no data file found",
+		},
+		{
+			name:    "empty directory",
+			flags:   []string{emptyDir},
+			wantErr: "contains no *.json files",
+		},
+		{
+			name:  "comma separated flag",
+			flags: []string{flagFile + "," + envFile},
+			want:  Source{Paths: []string{flagFile, envFile}, Origin: "flag"},
+		},
+		{
+			name: "path list separated env",
+			env:  envFile + string(os.PathListSeparator) + defaultFile,
+			want: Source{Paths: []string{envFile, defaultFile}, Origin: "env"},
+		},
+		{
+			name:  "repeated flags with blanks",
+			flags: []string{flagFile + ", ", " " + envFile},
+			want:  Source{Paths: []string{flagFile, envFile}, Origin: "flag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			DefaultPaths = tt.defaults
+
+			got, err := Resolve(tt.flags, tt.env)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"c.json", "a.json", "b.json"} {
+		writeDataFile(t, filepath.Join(dir, name), name)
+	}
+	// Non-JSON files and subdirectories are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	single := filepath.Join(t.TempDir(), "single.json")
+	writeDataFile(t, single, "s")
+
+	emptyDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "directory expands to sorted json files",
+			paths: []string{dir},
+			want: []string{
+				filepath.Join(dir, "a.json"),
+				filepath.Join(dir, "b.json"),
+				filepath.Join(dir, "c.json"),
+			},
+		},
+		{
+			name:  "files and directories keep argument order",
+			paths: []string{single, dir},
+			want: []string{
+				single,
+				filepath.Join(dir, "a.json"),
+				filepath.Join(dir, "b.json"),
+				filepath.Join(dir, "c.json"),
+			},
+		},
+		{
+			name:    "empty directory",
+			paths:   []string{emptyDir},
+			wantErr: "contains no *.json files",
+		},
+		{
+			name:    "missing path",
+			paths:   []string{filepath.Join(dir, "missing.json")},
+			wantErr: "missing.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Source{Paths: tt.paths, Origin: "flag"}.Files()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Files() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Files() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Files() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceLoadMerges(t *testing.T) {
+	dir := t.TempDir()
+	writeDataFile(t, filepath.Join(dir, "1.json"), "a", "b")
+	writeDataFile(t, filepath.Join(dir, "2.json"), "c", "a")
+	extra := filepath.Join(t.TempDir(), "extra.json")
+	writeDataFile(t, extra, "d")
+
+	c, err := Source{Paths: []string{dir, extra}, Origin: "flag"}.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var ids []string
+	for _, h := range c.Hotels {
+		ids = append(ids, h.GetHotelId())
+	}
+	if want := []string{"a", "b", "c", "a", "d"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("hotel ids = %v, want %v", ids, want)
+	}
+	if got := c.Metadata.TotalHotels; got != 5 {
+		t.Fatalf("metadata totalHotels = %d, want 5", got)
+	}
+	if got := len(c.Files); got != 3 {
+		t.Fatalf("files = %v, want 3 entries", c.Files)
+	}
+
+	// Duplicate ids resolve to the first file in load order
+	if h, ok := c.ByHotelID("a"); !ok || h != c.Hotels[0] {
+		t.Fatalf("ByHotelID(a) = %v, %v; want first loaded hotel", h, ok)
+	}
+	if _, ok := c.ByHotelID("d"); !ok {
+		t.Fatal("ByHotelID(d) not found")
+	}
+}
@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	pb "grpc-vs-http/proto"
+)
+
+// unknownKey groups hotels that lack the field a breakdown is keyed on
+const unknownKey = "unknown"
+
+// Aggregate counts the hotels matching f, with a breakdown for each
+// requested group. No groups means every breakdown.
+func Aggregate(hotels []*pb.Hotel, f *pb.HotelFilter, groups []pb.StatsGroup) *pb.HotelStats {
+	if len(groups) == 0 {
+		groups = []pb.StatsGroup{pb.StatsGroup_STATS_GROUP_COUNTRY, pb.StatsGroup_STATS_GROUP_CITY, pb.StatsGroup_STATS_GROUP_STARS}
+	}
+
+	var byCountry, byCity, byStars *bucketCounter
+	for _, group := range groups {
+		switch group {
+		case pb.StatsGroup_STATS_GROUP_COUNTRY:
+			byCountry = newBucketCounter()
+		case pb.StatsGroup_STATS_GROUP_CITY:
+			byCity = newBucketCounter()
+		case pb.StatsGroup_STATS_GROUP_STARS:
+			byStars = newBucketCounter()
+		}
+	}
+
+	stats := &pb.HotelStats{}
+	for _, h := range hotels {
+		if !Match(h, f) {
+			continue
+		}
+
+		available := h.Available != nil && *h.Available
+		stats.TotalHotels++
+		if available {
+			stats.AvailableHotels++
+		}
+
+		if byCountry != nil {
+			key, label := unknownKey, ""
+			if h.CountryCode != nil {
+				key = *h.CountryCode
+			}
+			if h.Country != nil {
+				label = *h.Country
+			}
+			byCountry.add(key, label, available)
+		}
+		if byCity != nil {
+			key, label := unknownKey, ""
+			if h.CityId != nil {
+				key = strconv.Itoa(int(*h.CityId))
+			}
+			if h.City != nil {
+				label = *h.City
+			}
+			byCity.add(key, label, available)
+		}
+		if byStars != nil {
+			key := unknownKey
+			if h.Rating != nil {
+				key = strconv.Itoa(int(math.Floor(float64(*h.Rating))))
+			}
+			byStars.add(key, "", available)
+		}
+	}
+
+	stats.ByCountry = byCountry.sorted()
+	stats.ByCity = byCity.sorted()
+	stats.ByStars = byStars.sorted()
+	return stats
+}
+
+// bucketCounter accumulates StatsBuckets by key
+type bucketCounter struct {
+	buckets map[string]*pb.StatsBucket
+}
+
+func newBucketCounter() *bucketCounter {
+	return &bucketCounter{buckets: make(map[string]*pb.StatsBucket)}
+}
+
+func (b *bucketCounter) add(key, label string, available bool) {
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &pb.StatsBucket{Key: key, Label: label}
+		b.buckets[key] = bucket
+	}
+	bucket.TotalHotels++
+	if available {
+		bucket.AvailableHotels++
+	}
+}
+
+// sorted returns the buckets ordered by key, or nil for a group that was not requested
+func (b *bucketCounter) sorted() []*pb.StatsBucket {
+	if b == nil {
+		return nil
+	}
+
+	buckets := make([]*pb.StatsBucket, 0, len(b.buckets))
+	for _, bucket := range b.buckets {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return keyLess(buckets[i].Key, buckets[j].Key) })
+	return buckets
+}
+
+// keyLess orders numeric keys (city ids, stars) numerically, before the
+// other keys, which are in string order
+func keyLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a < b
+	}
+}
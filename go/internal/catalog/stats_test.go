@@ -0,0 +1,41 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAggregateSortsKeys(t *testing.T) {
+	var hotels []*pb.Hotel
+	for _, cityID := range []int32{10, 9, 100, 2} {
+		hotels = append(hotels, &pb.Hotel{CityId: proto.Int32(cityID), CountryCode: proto.String("FR")})
+	}
+	hotels = append(hotels, &pb.Hotel{CountryCode: proto.String("DE")}) // No city id: "unknown"
+
+	stats := Aggregate(hotels, nil, []pb.StatsGroup{pb.StatsGroup_STATS_GROUP_CITY, pb.StatsGroup_STATS_GROUP_COUNTRY})
+
+	tests := []struct {
+		name    string
+		buckets []*pb.StatsBucket
+		want    []string
+	}{
+		{name: "numeric keys", buckets: stats.ByCity, want: []string{"2", "9", "10", "100", unknownKey}},
+		{name: "string keys", buckets: stats.ByCountry, want: []string{"DE", "FR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for _, bucket := range tt.buckets {
+				keys = append(keys, bucket.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Fatalf("keys = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package codec registers alternative gRPC codecs, to compare wire formats
+// for the same messages. A client picks one per call with
+// grpc.CallContentSubtype and the server answers with the codec named by the
+// call's content-subtype, so importing the package is all a server needs.
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec names, as sent in the content-subtype of a call
+const (
+	Proto   = "proto"   // gRPC's default protobuf codec
+	VTProto = "vtproto" // Protobuf, with the MarshalVT/UnmarshalVT code generated by vtprotobuf
+	JSON    = "json"    // protojson
+	MsgPack = "msgpack" // MessagePack of the generated structs, keyed by their JSON names
+)
+
+// Names lists the valid codec names, Proto first
+var Names = []string{Proto, VTProto, JSON, MsgPack}
+
+func init() {
+	encoding.RegisterCodec(vtprotoCodec{})
+	encoding.RegisterCodec(jsonCodec{})
+	encoding.RegisterCodec(msgpackCodec{})
+}
+
+// Parse checks a codec name. The empty string means Proto.
+func Parse(name string) (string, error) {
+	if name == "" {
+		return Proto, nil
+	}
+	for _, valid := range Names {
+		if name == valid {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("codec: %q is not one of %s", name, strings.Join(Names, ", "))
+}
+
+// Get returns the registered codec for a name
+func Get(name string) encoding.Codec {
+	return encoding.GetCodec(name)
+}
+
+// vtMessage is implemented by the messages generated by protoc-gen-go-vtproto
+type vtMessage interface {
+	MarshalVT() ([]byte, error)
+	UnmarshalVT([]byte) error
+}
+
+// vtprotoCodec produces the same bytes as Proto, with generated code instead
+// of reflection
+type vtprotoCodec struct{}
+
+func (vtprotoCodec) Name() string {
+	return VTProto
+}
+
+func (vtprotoCodec) Marshal(v any) ([]byte, error) {
+	if message, ok := v.(vtMessage); ok {
+		return message.MarshalVT()
+	}
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal %T: not a proto.Message", v)
+	}
+	return proto.Marshal(message)
+}
+
+func (vtprotoCodec) Unmarshal(data []byte, v any) error {
+	if message, ok := v.(vtMessage); ok {
+		return message.UnmarshalVT(data)
+	}
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal into %T: not a proto.Message", v)
+	}
+	return proto.Unmarshal(data, message)
+}
+
+// jsonCodec encodes messages as protojson
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return JSON
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal %T: not a proto.Message", v)
+	}
+	return protojson.Marshal(message)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal into %T: not a proto.Message", v)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, message)
+}
+
+// msgpackCodec encodes the generated structs by reflection. Oneof fields
+// cannot be decoded, so it does not suit GetHotel requests.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string {
+	return MsgPack
+}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	encoder.SetOmitEmpty(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
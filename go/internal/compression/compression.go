@@ -0,0 +1,178 @@
+// Package compression registers the gzip, zstd and snappy compressors with
+// gRPC's encoding registry. Importing it is all a server needs: it answers
+// every call with the compressor the client compressed its request with.
+// Clients pick one per call with grpc.UseCompressor.
+package compression
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Compressor names, as sent in the grpc-encoding header
+const (
+	None   = "none" // No compression; not registered with gRPC
+	Gzip   = gzip.Name
+	Zstd   = "zstd"
+	Snappy = "snappy"
+)
+
+// Names lists the valid compressor names, None first
+var Names = []string{None, Gzip, Zstd, Snappy}
+
+func init() {
+	encoding.RegisterCompressor(newZstdCompressor())
+	encoding.RegisterCompressor(newSnappyCompressor())
+}
+
+// Parse checks a compressor name. The empty string means None.
+func Parse(name string) (string, error) {
+	if name == "" {
+		return None, nil
+	}
+	for _, valid := range Names {
+		if name == valid {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("compression: %q is not one of %s", name, strings.Join(Names, ", "))
+}
+
+// zstdCompressor pools encoders and decoders, which are expensive to create
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+func newZstdCompressor() *zstdCompressor {
+	c := &zstdCompressor{}
+	c.encoders.New = func() any {
+		// Messages are compressed one at a time, extra goroutines would only add overhead
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		return encoder
+	}
+	c.decoders.New = func() any {
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		return decoder
+	}
+	return c
+}
+
+func (c *zstdCompressor) Name() string {
+	return Zstd
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	encoder := c.encoders.Get().(*zstd.Encoder)
+	encoder.Reset(w)
+	return &zstdWriter{Encoder: encoder, pool: &c.encoders}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	decoder := c.decoders.Get().(*zstd.Decoder)
+	if err := decoder.Reset(r); err != nil {
+		c.decoders.Put(decoder)
+		return nil, err
+	}
+	return &zstdReader{Decoder: decoder, pool: &c.decoders}, nil
+}
+
+// zstdWriter returns its encoder to the pool once the message is written
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	defer w.pool.Put(w.Encoder)
+	return w.Encoder.Close()
+}
+
+// zstdReader returns its decoder to the pool once the message is read
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	if r.Decoder == nil {
+		return 0, io.EOF
+	}
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Decoder)
+		r.Decoder = nil
+	}
+	return n, err
+}
+
+// snappyCompressor uses the snappy framing format, which streams
+type snappyCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func newSnappyCompressor() *snappyCompressor {
+	c := &snappyCompressor{}
+	c.writers.New = func() any { return snappy.NewBufferedWriter(nil) }
+	c.readers.New = func() any { return snappy.NewReader(nil) }
+	return c
+}
+
+func (c *snappyCompressor) Name() string {
+	return Snappy
+}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	writer := c.writers.Get().(*snappy.Writer)
+	writer.Reset(w)
+	return &snappyWriter{Writer: writer, pool: &c.writers}, nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	reader := c.readers.Get().(*snappy.Reader)
+	reader.Reset(r)
+	return &snappyReader{Reader: reader, pool: &c.readers}, nil
+}
+
+// snappyWriter returns its writer to the pool once the message is written
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+func (w *snappyWriter) Close() error {
+	defer w.pool.Put(w.Writer)
+	return w.Writer.Close()
+}
+
+// snappyReader returns its reader to the pool once the message is read
+type snappyReader struct {
+	*snappy.Reader
+	pool *sync.Pool
+}
+
+func (r *snappyReader) Read(p []byte) (int, error) {
+	if r.Reader == nil {
+		return 0, io.EOF
+	}
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Reader)
+		r.Reader = nil
+	}
+	return n, err
+}
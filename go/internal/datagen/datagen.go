@@ -0,0 +1,425 @@
+// Package datagen builds fake hotel datasets compatible with catalog.DataFile.
+// Output depends only on the Config, so a seed reproduces the same dataset.
+package datagen
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"time"
+
+	"grpc-vs-http/internal/catalog"
+	pb "grpc-vs-http/proto"
+)
+
+// GeneratedBy is recorded in the metadata of every generated dataset
+const GeneratedBy = "GoFakeHotelGenerator"
+
+// Config controls the shape and size of a generated dataset
+type Config struct {
+	Seed          int64     // Seed of the random source
+	Hotels        int       // Number of hotels (upper bound when MaxBytes is set, 0 = unbounded)
+	RoomsPerHotel int       // Rooms in each hotel
+	RatesPerRoom  int       // Rates in each room
+	Reviews       int       // Reviews per hotel
+	Photos        int       // Photo URLs per hotel
+	MaxBytes      int64     // Keep the output within this size (0 = no limit)
+	Minimal       bool      // Only ids, name, location, rate and availability; see MinimalHotel
+	GeneratedAt   time.Time // Timestamp written to the metadata (zero = now)
+}
+
+// DefaultConfig mirrors the average shape produced by the Node.js generator
+func DefaultConfig() Config {
+	return Config{
+		Seed:          1,
+		Hotels:        1000,
+		RoomsPerHotel: 35,
+		RatesPerRoom:  2,
+		Reviews:       2,
+		Photos:        2,
+	}
+}
+
+var (
+	hotelNames = []string{
+		"Grand Palace Hotel", "Ocean View Resort", "City Center Inn", "Mountain Lodge",
+		"Sunset Beach Hotel", "Royal Garden Hotel", "Urban Boutique Hotel", "Seaside Resort",
+		"Metropolitan Hotel", "Paradise Resort", "Golden Gate Hotel", "Riverside Inn",
+	}
+	cities = []string{
+		"New York", "Los Angeles", "Chicago", "Houston", "Phoenix", "Philadelphia",
+		"San Antonio", "San Diego", "Dallas", "San Jose", "Austin", "Jacksonville",
+	}
+	countries    = []string{"USA", "Canada", "Mexico", "UK", "France", "Germany", "Spain", "Italy"}
+	countryCodes = []string{"US", "CA", "MX", "GB", "FR", "DE", "ES", "IT"}
+	zones        = []string{"Downtown", "Airport", "Beach", "Mountain", "Suburban", "Historic District"}
+	currencies   = []string{"USD", "EUR", "GBP", "CAD"}
+	boards       = []string{"BB", "HB", "FB", "AI", "RO"}
+	roomTypes    = []string{"Standard", "Deluxe", "Suite", "Executive", "Presidential"}
+	tags         = []string{"family", "business", "luxury", "budget", "romantic", "adventure"}
+)
+
+// Generator produces hotels from a seeded random source
+type Generator struct {
+	cfg Config
+	rng *rand.Rand
+}
+
+// New creates a generator for the given configuration
+func New(cfg Config) *Generator {
+	return &Generator{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+// Hotel generates the hotel with the given 1-based id. Hotels must be
+// requested in order for the output to be reproducible.
+func (g *Generator) Hotel(id int) *pb.Hotel {
+	hotel := g.fullHotel(id)
+	if g.cfg.Minimal {
+		return MinimalHotel(hotel)
+	}
+	return hotel
+}
+
+// MinimalHotel keeps the fields of h that the services filter, index and
+// count on, and drops the rest. A minimal hotel takes a few hundred bytes of
+// JSON instead of about 50 KB, for datasets of a few kilobytes.
+func MinimalHotel(h *pb.Hotel) *pb.Hotel {
+	return &pb.Hotel{
+		HotelId:     h.HotelId,
+		GiataId:     h.GiataId,
+		HUid:        h.HUid,
+		Name:        h.Name,
+		Rating:      h.Rating,
+		City:        h.City,
+		CityId:      h.CityId,
+		Country:     h.Country,
+		CountryCode: h.CountryCode,
+		MinRate:     h.MinRate,
+		Currency:    h.Currency,
+		Available:   h.Available,
+	}
+}
+
+func (g *Generator) fullHotel(id int) *pb.Hotel {
+	r := g.rng
+	country := r.Intn(len(countries))
+	city := r.Intn(len(cities))
+	zone := r.Intn(len(zones))
+	name := hotelNames[r.Intn(len(hotelNames))]
+	currency := currencies[r.Intn(len(currencies))]
+	score := round2(6 + r.Float64()*4)
+	minRate := float64(50 + r.Intn(200))
+	code := fmt.Sprintf("HTL%06d", id)
+
+	photos := make([]string, g.cfg.Photos)
+	for i := range photos {
+		photos[i] = fmt.Sprintf("https://example.com/hotel%d/photo%d.jpg", id, i+1)
+	}
+
+	return &pb.Hotel{
+		SupplierId:    i32(1000 + int32(r.Intn(100))),
+		SupplierIds:   []int32{1000 + int32(r.Intn(100)), 2000 + int32(r.Intn(50))},
+		HotelId:       &code,
+		HotelIds:      []string{code, fmt.Sprintf("ALT%06d", id)},
+		GiataId:       i32(100000 + int32(id)),
+		HUid:          i32(500000 + int32(id)),
+		Name:          str(fmt.Sprintf("%s %d", name, id)),
+		Rating:        f32(float32(1 + r.Intn(5))),
+		Address:       str(fmt.Sprintf("%d Main Street, %s", 100+r.Intn(900), cities[city])),
+		Score:         &score,
+		HotelChainId:  i32(10 + int32(r.Intn(20))),
+		AccTypeId:     i32(1 + int32(r.Intn(5))),
+		City:          str(cities[city]),
+		CityId:        i32(1000 + int32(city)),
+		ZoneId:        int32(1 + r.Intn(10)),
+		Zone:          zones[zone],
+		Country:       str(countries[country]),
+		CountryCode:   str(countryCodes[country]),
+		CountryId:     i32(1 + int32(country)),
+		Lat:           f64(round2(40 + r.Float64()*10)),
+		Long:          f64(round2(-74 + r.Float64()*10)),
+		MarketingText: str(fmt.Sprintf("Experience luxury and comfort at %s. Perfect for your stay.", name)),
+		MinRate:       &minRate,
+		MaxRate:       f64(minRate + float64(150+r.Intn(500))),
+		Currency:      &currency,
+		Photos:        photos,
+		Rooms:         g.rooms(id, currency),
+		Supplements: []*pb.Supplement{
+			{Name: str("Breakfast"), Amount: f64(float64(15 + r.Intn(20))), Currency: &currency, Included: b(r.Intn(3) == 0)},
+			{Name: str("Parking"), Amount: f64(float64(10 + r.Intn(15))), Currency: &currency, Included: b(r.Intn(4) == 0)},
+		},
+		Total: f32(float32(150 + r.Intn(300))),
+		Distances: map[string]float32{
+			"airport":     float32(5 + r.Intn(20)),
+			"city_center": float32(2 + r.Intn(10)),
+			"beach":       float32(1 + r.Intn(15)),
+		},
+		Neighborhood: &pb.Neighborhood{
+			Name:        zones[zone] + " Area",
+			Description: "Prime location with easy access to attractions",
+		},
+		Strength: map[string]bool{
+			"location":   r.Intn(2) == 0,
+			"service":    r.Intn(3) == 0,
+			"facilities": r.Intn(4) == 0,
+		},
+		Review: &pb.Review{
+			Score:   score,
+			Count:   int32(50 + r.Intn(200)),
+			Average: score,
+		},
+		Available:                b(r.Intn(10) != 0), // 90% availability
+		Boards:                   boards[:1+r.Intn(3)],
+		Tag:                      str(tags[r.Intn(len(tags))]),
+		CityLat:                  f64(40 + float64(city%10)),
+		CityLong:                 f64(-74 + float64(city%10)),
+		Reviews:                  g.reviews(id),
+		ReviewsSubratingsAverage: g.subratings(),
+		AllNRF:                   b(r.Intn(5) == 0),
+		AllRF:                    b(r.Intn(7) == 0),
+		PartialNRF:               b(r.Intn(3) == 0),
+	}
+}
+
+func (g *Generator) rooms(hotelID int, currency string) []*pb.Room {
+	r := g.rng
+	rooms := make([]*pb.Room, g.cfg.RoomsPerHotel)
+	for i := range rooms {
+		roomType := i % len(roomTypes)
+		code := fmt.Sprintf("RM%d%03d", hotelID, i)
+		label := fmt.Sprintf("%s Room %d%02d", roomTypes[roomType], i/20+1, i%20+1)
+
+		rooms[i] = &pb.Room{
+			Code:         &code,
+			Codes:        []string{code, fmt.Sprintf("ALT%d%03d", hotelID, i)},
+			Name:         &label,
+			Names:        []string{label},
+			Rates:        g.rates(hotelID, i, currency),
+			Category:     str(roomTypes[roomType]),
+			Total:        f64(float64(100 + r.Intn(200) + roomType*50)),
+			OriginalCode: str(fmt.Sprintf("ORIG%d%03d", hotelID, i)),
+			OriginalName: str("Original " + label),
+		}
+	}
+	return rooms
+}
+
+func (g *Generator) rates(hotelID, roomIndex int, currency string) []*pb.Rate {
+	r := g.rng
+	rates := make([]*pb.Rate, g.cfg.RatesPerRoom)
+	for i := range rates {
+		board := boards[i%len(boards)]
+		amount := float64(80 + r.Intn(150) + i*20)
+
+		rates[i] = &pb.Rate{
+			RateKey:      str(fmt.Sprintf("RK%d-%d-%d", hotelID, roomIndex, i)),
+			RateClass:    str("NOR"),
+			ContractId:   i32(1000 + int32(r.Intn(100))),
+			RateType:     str("BOOKABLE"),
+			PaymentType:  str("AT_HOTEL"),
+			Allotment:    i32(5 + int32(r.Intn(10))),
+			Availability: str("OK"),
+			Amount:       &amount,
+			Currency:     &currency,
+			BoardCode:    &board,
+			BoardName:    str(board + " Board"),
+			Nrf:          b(r.Intn(3) == 0),
+			CancellationPolicies: []*pb.CancellationPolicy{{
+				Amount:        f64(50),
+				From:          str("2024-12-01"),
+				RealFrom:      str("2024-12-01T00:00:00"),
+				Name:          str("Non-refundable"),
+				PurchasePrice: f64(45),
+			}},
+			Taxes: []*pb.Tax{{
+				Name:     str("City Tax"),
+				Amount:   f64(5),
+				Currency: &currency,
+				Included: b(false),
+				Type:     str("LOCAL"),
+			}},
+			Rooms:         i32(1 + int32(r.Intn(3))),
+			Adults:        str("2"),
+			Children:      str("0"),
+			Infant:        str("0"),
+			ChildrenAges:  str(""),
+			RateComments:  str("Standard rate conditions apply"),
+			Packaging:     b(false),
+			Total:         f64(amount + 20),
+			PurchasePrice: f64(amount - 10),
+		}
+	}
+	return rates
+}
+
+func (g *Generator) reviews(hotelID int) []*pb.HotelReview {
+	r := g.rng
+	reviews := make([]*pb.HotelReview, g.cfg.Reviews)
+	for i := range reviews {
+		reviews[i] = &pb.HotelReview{
+			Id:         fmt.Sprintf("REV%d-%d", hotelID, i),
+			Rating:     float32(5 + r.Intn(6)),
+			Comment:    fmt.Sprintf("Great stay at hotel %d. Excellent service and facilities.", hotelID),
+			Author:     fmt.Sprintf("Guest%d-%d", hotelID, i),
+			Date:       "2024-01-01",
+			Subratings: g.subratings(),
+		}
+	}
+	return reviews
+}
+
+func (g *Generator) subratings() map[string]float32 {
+	r := g.rng
+	return map[string]float32{
+		"cleanliness": float32(7 + r.Intn(3)),
+		"service":     float32(6 + r.Intn(4)),
+		"location":    float32(8 + r.Intn(2)),
+		"value":       float32(7 + r.Intn(3)),
+	}
+}
+
+// Catalog generates cfg.Hotels hotels in memory, for tests and benchmarks
+// that do not need a file on disk. MaxBytes is ignored.
+func Catalog(cfg Config) *catalog.Catalog {
+	g := New(cfg)
+	hotels := make([]*pb.Hotel, cfg.Hotels)
+	for i := range hotels {
+		hotels[i] = g.Hotel(i + 1)
+	}
+
+	return catalog.New(hotels, &pb.Metadata{
+		GeneratedAt:  generatedAt(cfg),
+		TotalHotels:  int32(cfg.Hotels),
+		GeneratedBy:  GeneratedBy,
+		ActualHotels: int32(cfg.Hotels),
+	})
+}
+
+// Write streams a data file to w without holding the dataset in memory.
+// Hotels are written before the metadata, which is only known at the end.
+// With MaxBytes, hotels are added while the whole file, metadata included,
+// stays within it. When not even one hotel of the configured shape fits,
+// every hotel is written minimal instead; a limit below a single minimal
+// hotel is an error.
+func Write(w io.Writer, cfg Config, progress func(hotels int, bytes int64)) (*pb.Metadata, error) {
+	if cfg.Hotels <= 0 && cfg.MaxBytes <= 0 {
+		return nil, fmt.Errorf("either a hotel count or a maximum size is required")
+	}
+
+	timestamp := generatedAt(cfg)
+	reserve := metadataReserve(timestamp)
+	if cfg.MaxBytes > 0 && !cfg.Minimal {
+		first, err := json.Marshal(New(cfg).Hotel(1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(`{"hotels":[`)+len(first))+reserve > cfg.MaxBytes {
+			cfg.Minimal = true
+		}
+	}
+
+	buf := bufio.NewWriterSize(w, 1<<20)
+	cw := &countingWriter{w: buf}
+	g := New(cfg)
+
+	io.WriteString(cw, `{"hotels":[`)
+	hotels := 0
+	for cfg.Hotels <= 0 || hotels < cfg.Hotels {
+		encoded, err := json.Marshal(g.Hotel(hotels + 1))
+		if err != nil {
+			return nil, err
+		}
+		if size := cw.n + 1 + int64(len(encoded)) + reserve; cfg.MaxBytes > 0 && size > cfg.MaxBytes { // 1 for the separator
+			if hotels == 0 {
+				return nil, fmt.Errorf("a maximum size of %d bytes does not fit a single hotel, which needs %d bytes", cfg.MaxBytes, size)
+			}
+			break
+		}
+		if hotels > 0 {
+			io.WriteString(cw, ",")
+		}
+		cw.Write(encoded)
+		hotels++
+
+		if progress != nil && hotels%1000 == 0 {
+			progress(hotels, cw.n)
+		}
+	}
+	io.WriteString(cw, `],"metadata":`)
+
+	requested := cfg.Hotels
+	if requested <= 0 {
+		requested = hotels
+	}
+	metadata := &pb.Metadata{
+		GeneratedAt:  timestamp,
+		TotalHotels:  int32(requested),
+		GeneratedBy:  GeneratedBy,
+		ActualSizeMB: round2(float64(cw.n) / (1024 * 1024)),
+		ActualHotels: int32(hotels),
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	cw.Write(encoded)
+	io.WriteString(cw, "}\n")
+
+	if cw.err != nil {
+		return nil, cw.err
+	}
+	if err := buf.Flush(); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// metadataReserve bounds the bytes Write adds after the last hotel: the
+// closing of the hotels array and the metadata with the longest counters
+func metadataReserve(timestamp string) int64 {
+	encoded, _ := json.Marshal(&pb.Metadata{
+		GeneratedAt:  timestamp,
+		TotalHotels:  math.MaxInt32,
+		GeneratedBy:  GeneratedBy,
+		ActualSizeMB: 99999.99,
+		ActualHotels: math.MaxInt32,
+	})
+	return int64(len(`],"metadata":`) + len(encoded) + len("}\n"))
+}
+
+// countingWriter counts bytes and remembers the first write error, so the
+// generation loop only has to check once at the end
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func generatedAt(cfg Config) string {
+	t := cfg.GeneratedAt
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+func str(v string) *string   { return &v }
+func i32(v int32) *int32     { return &v }
+func f32(v float32) *float32 { return &v }
+func f64(v float64) *float64 { return &v }
+func b(v bool) *bool         { return &v }
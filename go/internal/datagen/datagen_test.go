@@ -0,0 +1,79 @@
+package datagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"grpc-vs-http/internal/catalog"
+)
+
+func TestWriteMaxBytes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Hotels = 0
+
+	for _, maxBytes := range []int64{60 << 10, 200 << 10, 1 << 20} {
+		cfg.MaxBytes = maxBytes
+
+		var buf bytes.Buffer
+		metadata, err := Write(&buf, cfg, nil)
+		if err != nil {
+			t.Fatalf("Write(MaxBytes=%d) error = %v", maxBytes, err)
+		}
+		if int64(buf.Len()) > maxBytes {
+			t.Fatalf("Write(MaxBytes=%d) wrote %d bytes", maxBytes, buf.Len())
+		}
+		if metadata.ActualHotels < 1 {
+			t.Fatalf("Write(MaxBytes=%d) wrote no hotels", maxBytes)
+		}
+
+		var data catalog.DataFile
+		if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+			t.Fatalf("Write(MaxBytes=%d) output is not valid JSON: %v", maxBytes, err)
+		}
+	}
+}
+
+func TestWriteMaxBytes1KB(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Hotels = 0
+	cfg.MaxBytes = 1 << 10
+
+	var buf bytes.Buffer
+	metadata, err := Write(&buf, cfg, nil)
+	if err != nil {
+		t.Fatalf("Write(MaxBytes=1KB) error = %v", err)
+	}
+	if buf.Len() > 1<<10 {
+		t.Fatalf("Write(MaxBytes=1KB) wrote %d bytes", buf.Len())
+	}
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := catalog.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := len(c.Hotels); got < 1 || got != int(metadata.ActualHotels) {
+		t.Fatalf("loaded %d hotels, metadata says %d", got, metadata.ActualHotels)
+	}
+	if _, ok := c.ByHotelID(c.Hotels[0].GetHotelId()); !ok {
+		t.Fatal("minimal hotels are not indexed by hotelId")
+	}
+}
+
+func TestWriteMaxBytesBelowOneHotel(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Hotels = 0
+	cfg.MaxBytes = 100
+
+	_, err := Write(&bytes.Buffer{}, cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "does not fit a single hotel") {
+		t.Fatalf("Write(MaxBytes=1KB) error = %v, want a single hotel error", err)
+	}
+}
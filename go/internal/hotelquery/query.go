@@ -0,0 +1,203 @@
+// Package hotelquery maps hotel stream parameters to and from URL query
+// strings, so the gateway and the HTTP microservice agree on their names.
+package hotelquery
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"grpc-vs-http/internal/catalog"
+	pb "grpc-vs-http/proto"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ChunkSize reads ?chunkSize=, defaulting to catalog.DefaultChunkSize when absent or invalid
+func ChunkSize(values url.Values) int32 {
+	chunkSize := int32(catalog.DefaultChunkSize)
+	if chunkParam := values.Get("chunkSize"); chunkParam != "" {
+		if parsed, err := strconv.ParseInt(chunkParam, 10, 32); err == nil && parsed > 0 {
+			chunkSize = int32(parsed)
+		}
+	}
+	return chunkSize
+}
+
+// Fields reads ?fields= as a field mask, or nil when absent
+func Fields(values url.Values) *fieldmaskpb.FieldMask {
+	if fields := List(values, "fields"); len(fields) > 0 {
+		return &fieldmaskpb.FieldMask{Paths: fields}
+	}
+	return nil
+}
+
+// StreamRequest builds a StreamRequest from the chunkSize, filter and fields parameters
+func StreamRequest(values url.Values) (*pb.StreamRequest, error) {
+	filter, err := Filter(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StreamRequest{
+		ChunkSize: ChunkSize(values),
+		Filter:    filter,
+		Fields:    Fields(values),
+	}, nil
+}
+
+// EncodeStreamRequest is the inverse of StreamRequest
+func EncodeStreamRequest(req *pb.StreamRequest) url.Values {
+	values := url.Values{}
+	if req.ChunkSize > 0 {
+		values.Set("chunkSize", strconv.Itoa(int(req.ChunkSize)))
+	}
+	EncodeFilter(req.Filter, values)
+	if paths := req.GetFields().GetPaths(); len(paths) > 0 {
+		values.Set("fields", strings.Join(paths, ","))
+	}
+	return values
+}
+
+// ListHotelsRequest builds a ListHotelsRequest from the pageSize, pageToken,
+// filter and fields parameters
+func ListHotelsRequest(values url.Values) (*pb.ListHotelsRequest, error) {
+	filter, err := Filter(values)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.ListHotelsRequest{
+		PageToken: values.Get("pageToken"),
+		Filter:    filter,
+		Fields:    Fields(values),
+	}
+	if value := values.Get("pageSize"); value != "" {
+		pageSize, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || pageSize < 0 {
+			return nil, fmt.Errorf("pageSize: %q is not a positive integer", value)
+		}
+		req.PageSize = int32(pageSize)
+	}
+	return req, nil
+}
+
+// Filter reads the hotel filter query parameters. List parameters accept
+// comma-separated values or repeated keys. It returns nil when no filter
+// parameter is present.
+func Filter(values url.Values) (*pb.HotelFilter, error) {
+	f := &pb.HotelFilter{
+		CountryCodes: List(values, "country"),
+		Boards:       List(values, "board"),
+		Tags:         List(values, "tag"),
+	}
+
+	for _, value := range List(values, "cityId") {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("cityId: %q is not an integer", value)
+		}
+		f.CityIds = append(f.CityIds, int32(id))
+	}
+
+	var err error
+	if f.MinRating, err = float32Param(values, "minRating"); err != nil {
+		return nil, err
+	}
+	if f.MaxRating, err = float32Param(values, "maxRating"); err != nil {
+		return nil, err
+	}
+	if f.MinPrice, err = float64Param(values, "minPrice"); err != nil {
+		return nil, err
+	}
+	if f.MaxPrice, err = float64Param(values, "maxPrice"); err != nil {
+		return nil, err
+	}
+	if values.Has("available") {
+		value := values.Get("available")
+		available, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("available: %q is not a boolean", value)
+		}
+		f.Available = &available
+	}
+
+	if catalog.IsEmptyFilter(f) {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// EncodeFilter adds the query parameters Filter reads back as f
+func EncodeFilter(f *pb.HotelFilter, values url.Values) {
+	if catalog.IsEmptyFilter(f) {
+		return
+	}
+
+	setList(values, "country", f.CountryCodes)
+	setList(values, "board", f.Boards)
+	setList(values, "tag", f.Tags)
+	for _, id := range f.CityIds {
+		values.Add("cityId", strconv.Itoa(int(id)))
+	}
+	if f.MinRating != nil {
+		values.Set("minRating", strconv.FormatFloat(float64(*f.MinRating), 'g', -1, 32))
+	}
+	if f.MaxRating != nil {
+		values.Set("maxRating", strconv.FormatFloat(float64(*f.MaxRating), 'g', -1, 32))
+	}
+	if f.MinPrice != nil {
+		values.Set("minPrice", strconv.FormatFloat(*f.MinPrice, 'g', -1, 64))
+	}
+	if f.MaxPrice != nil {
+		values.Set("maxPrice", strconv.FormatFloat(*f.MaxPrice, 'g', -1, 64))
+	}
+	if f.Available != nil {
+		values.Set("available", strconv.FormatBool(*f.Available))
+	}
+}
+
+// List collects every value of a query parameter, splitting on commas
+func List(values url.Values, key string) []string {
+	var list []string
+	for _, raw := range values[key] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				list = append(list, value)
+			}
+		}
+	}
+	return list
+}
+
+func setList(values url.Values, key string, list []string) {
+	for _, value := range list {
+		values.Add(key, value)
+	}
+}
+
+func float64Param(values url.Values, key string) (*float64, error) {
+	if !values.Has(key) {
+		return nil, nil
+	}
+	value := values.Get(key)
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q is not a number", key, value)
+	}
+	return &parsed, nil
+}
+
+func float32Param(values url.Values, key string) (*float32, error) {
+	if !values.Has(key) {
+		return nil, nil
+	}
+	value := values.Get(key)
+	parsed, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q is not a number", key, value)
+	}
+	v := float32(parsed)
+	return &v, nil
+}
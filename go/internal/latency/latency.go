@@ -0,0 +1,81 @@
+// Package latency records request latencies in HDR histograms, shared by the
+// gateway's /concurrent-stats and cmd/loadgen.
+package latency
+
+import (
+	"io"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Histogram bounds: 1µs to 1h with 3 significant digits, i.e. values are
+// exact to within 0.1%
+const (
+	lowestMicros  = 1
+	highestMicros = int64(time.Hour / time.Microsecond)
+	sigFigs       = 3
+)
+
+// Histogram records latencies at microsecond resolution. It is not safe for
+// concurrent use.
+type Histogram struct {
+	h *hdrhistogram.Histogram
+}
+
+// NewHistogram creates an empty histogram
+func NewHistogram() *Histogram {
+	return &Histogram{h: hdrhistogram.New(lowestMicros, highestMicros, sigFigs)}
+}
+
+// Record adds one latency. Values outside 1µs..1h are clamped to the bounds.
+func (h *Histogram) Record(d time.Duration) {
+	micros := max(lowestMicros, min(d.Microseconds(), highestMicros))
+	h.h.RecordValue(micros)
+}
+
+// Count returns the number of recorded latencies
+func (h *Histogram) Count() int64 {
+	return h.h.TotalCount()
+}
+
+// Summary is the JSON form of a histogram. All values are in microseconds.
+type Summary struct {
+	Count    int64   `json:"count"`
+	MinUs    int64   `json:"minUs"`
+	MeanUs   float64 `json:"meanUs"`
+	StdDevUs float64 `json:"stdDevUs"`
+	P50Us    int64   `json:"p50Us"`
+	P90Us    int64   `json:"p90Us"`
+	P95Us    int64   `json:"p95Us"`
+	P99Us    int64   `json:"p99Us"`
+	P999Us   int64   `json:"p999Us"`
+	MaxUs    int64   `json:"maxUs"`
+}
+
+// Summary returns the count, spread and percentiles, or nil when nothing
+// was recorded
+func (h *Histogram) Summary() *Summary {
+	if h.h.TotalCount() == 0 {
+		return nil
+	}
+	return &Summary{
+		Count:    h.h.TotalCount(),
+		MinUs:    h.h.Min(),
+		MeanUs:   h.h.Mean(),
+		StdDevUs: h.h.StdDev(),
+		P50Us:    h.h.ValueAtQuantile(50),
+		P90Us:    h.h.ValueAtQuantile(90),
+		P95Us:    h.h.ValueAtQuantile(95),
+		P99Us:    h.h.ValueAtQuantile(99),
+		P999Us:   h.h.ValueAtQuantile(99.9),
+		MaxUs:    h.h.Max(),
+	}
+}
+
+// WritePercentiles writes the percentile distribution in milliseconds, in
+// the .hgrm text format accepted by HdrHistogram's plotting tools
+func (h *Histogram) WritePercentiles(w io.Writer) error {
+	_, err := h.h.PercentilesPrint(w, 5, float64(time.Millisecond/time.Microsecond))
+	return err
+}
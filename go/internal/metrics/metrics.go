@@ -0,0 +1,132 @@
+// Package metrics exposes the Prometheus metrics of the gateway and the
+// microservice: gRPC interceptor metrics, HTTP request latencies per route,
+// hotel stream counters and Go runtime stats. Each binary keeps its own
+// registry and serves it on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// latencyBuckets span 1ms to about 33s, the longest a stream may take
+var latencyBuckets = prometheus.ExponentialBuckets(0.001, 2, 16)
+
+// Metrics holds the collectors of one binary
+type Metrics struct {
+	registry *prometheus.Registry
+	hotels   *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+	active   *prometheus.GaugeVec
+	requests *prometheus.HistogramVec
+}
+
+// New creates the registry of a binary, with the stream metrics and the Go
+// runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		hotels: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hotels_streamed_total",
+			Help: "Hotels sent or received in hotel streams, by transport.",
+		}, []string{"transport"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hotel_stream_bytes_total",
+			Help: "Bytes of hotel streams sent or received on the wire, by transport.",
+		}, []string{"transport"}),
+		active: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hotel_streams_active",
+			Help: "Hotel streams in progress, by transport.",
+		}, []string{"transport"}),
+		requests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of the HTTP requests served, by method, route and status code.",
+			Buckets: latencyBuckets,
+		}, []string{"method", "route", "code"}),
+	}
+	m.registry.MustRegister(
+		m.hotels, m.bytes, m.active, m.requests,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// GRPCServer registers gRPC server metrics: calls started and handled by
+// method and code, handling time, and stream messages sent and received.
+// Install its interceptors, then call InitializeMetrics with the server.
+func (m *Metrics) GRPCServer() *grpcprom.ServerMetrics {
+	server := grpcprom.NewServerMetrics(
+		grpcprom.WithServerHandlingTimeHistogram(grpcprom.WithHistogramBuckets(latencyBuckets)),
+	)
+	m.registry.MustRegister(server)
+	return server
+}
+
+// GRPCClient registers gRPC client metrics: the same as GRPCServer, plus
+// the time spent waiting for each stream message
+func (m *Metrics) GRPCClient() *grpcprom.ClientMetrics {
+	client := grpcprom.NewClientMetrics(
+		grpcprom.WithClientHandlingTimeHistogram(grpcprom.WithHistogramBuckets(latencyBuckets)),
+		grpcprom.WithClientStreamRecvHistogram(grpcprom.WithHistogramBuckets(latencyBuckets)),
+	)
+	m.registry.MustRegister(client)
+	return client
+}
+
+// Gin records the latency of every request under its route pattern, so
+// /hotels/:id is one series whatever the id
+func (m *Metrics) Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.requests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Stream counts the chunks of one hotel stream
+type Stream struct {
+	hotels prometheus.Counter
+	bytes  prometheus.Counter
+	active prometheus.Gauge
+}
+
+// StreamStarted marks a stream over transport as active until Done
+func (m *Metrics) StreamStarted(transport string) *Stream {
+	stream := &Stream{
+		hotels: m.hotels.WithLabelValues(transport),
+		bytes:  m.bytes.WithLabelValues(transport),
+		active: m.active.WithLabelValues(transport),
+	}
+	stream.active.Inc()
+	return stream
+}
+
+// Chunk records one chunk of the given number of hotels and encoded size
+func (s *Stream) Chunk(hotels, bytes int) {
+	s.hotels.Add(float64(hotels))
+	s.bytes.Add(float64(bytes))
+}
+
+// Done marks the stream as finished
+func (s *Stream) Done() {
+	s.active.Dec()
+}
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc/stats"
+)
+
+// WireCounter counts the bytes of one stream as they cross the wire: each
+// message as encoded by its codec, with its framing, compressed when the
+// call is. Transports add to it as they move messages, which costs nothing
+// per chunk on top of the transfer. It must be used from the goroutine that
+// sends or receives the stream's messages; a nil counter counts nothing.
+type WireCounter struct {
+	bytes int64
+}
+
+type wireCounterKey struct{}
+
+// WithWireCounter returns a context carrying a new counter, and the counter
+func WithWireCounter(ctx context.Context) (context.Context, *WireCounter) {
+	counter := &WireCounter{}
+	return context.WithValue(ctx, wireCounterKey{}, counter), counter
+}
+
+// WireCounterFrom returns the counter of ctx, or nil
+func WireCounterFrom(ctx context.Context) *WireCounter {
+	counter, _ := ctx.Value(wireCounterKey{}).(*WireCounter)
+	return counter
+}
+
+// Add counts n bytes
+func (c *WireCounter) Add(n int) {
+	if c != nil {
+		c.bytes += int64(n)
+	}
+}
+
+// Bytes returns the bytes counted so far
+func (c *WireCounter) Bytes() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.bytes
+}
+
+// ClientWireStats is a gRPC client stats handler that counts the messages
+// each call receives into the WireCounter of the call's context. gRPC
+// reports them from the goroutine that receives them.
+type ClientWireStats struct{}
+
+func (ClientWireStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (ClientWireStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InPayload); ok {
+		WireCounterFrom(ctx).Add(in.WireLength)
+	}
+}
+
+func (ClientWireStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (ClientWireStats) HandleConn(context.Context, stats.ConnStats) {}
+
+// WireTransport wraps an HTTP client transport so the response bodies of
+// requests whose context carries a WireCounter are counted as they are read
+func WireTransport(next http.RoundTripper) http.RoundTripper {
+	return wireTransport{next: next}
+}
+
+type wireTransport struct {
+	next http.RoundTripper
+}
+
+func (t wireTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if counter := WireCounterFrom(req.Context()); counter != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, counter: counter}
+	}
+	return resp, nil
+}
+
+// countingBody counts the bytes read from a response body
+type countingBody struct {
+	io.ReadCloser
+	counter *WireCounter
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.counter.Add(n)
+	return n, err
+}
+
+// ServerWireStats is a gRPC server stats handler that gives every call a
+// WireCounter and counts the messages the call sends into it. gRPC reports
+// them from the goroutine that sends them, before SendMsg returns.
+type ServerWireStats struct{}
+
+func (ServerWireStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	ctx, _ = WithWireCounter(ctx)
+	return ctx
+}
+
+func (ServerWireStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if out, ok := s.(*stats.OutPayload); ok {
+		WireCounterFrom(ctx).Add(out.WireLength)
+	}
+}
+
+func (ServerWireStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (ServerWireStats) HandleConn(context.Context, stats.ConnStats) {}
+
+// WireHandler gives every request a WireCounter and counts the response
+// body the handler writes into it
+func WireHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, counter := WithWireCounter(r.Context())
+		next.ServeHTTP(&countingResponseWriter{ResponseWriter: w, counter: counter}, r.WithContext(ctx))
+	})
+}
+
+// countingResponseWriter counts the bytes written to a response. It keeps
+// the wrapped writer's flushing, which streaming handlers require.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	counter *WireCounter
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.counter.Add(n)
+	return n, err
+}
+
+func (w *countingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer
+func (w *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,116 @@
+// Package tracing sets up OpenTelemetry tracing for the gateway and the
+// microservice. Spans go to an OTLP collector, to stdout or to a file, and
+// the trace context travels between the two in gRPC metadata (W3C Trace
+// Context headers).
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Exporters accepted by Setup, besides a file path
+const (
+	OTLP   = "otlp"   // OTLP over gRPC, configured by the OTEL_EXPORTER_OTLP_* variables
+	Stdout = "stdout" // One JSON document per span
+)
+
+// otlpPrefix selects OTLP over gRPC to an explicit, plaintext endpoint,
+// e.g. otlp://localhost:4317
+const otlpPrefix = "otlp://"
+
+// Setup installs the global tracer provider and propagator of a service.
+// exporter is "otlp", "otlp://host:port", "stdout", or the path of a file
+// to append spans to as JSON. The returned function flushes the spans still
+// buffered; call it before exiting.
+func Setup(service, exporter string) (func(context.Context) error, error) {
+	spanExporter, closer, err := newExporter(exporter)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}, nil
+}
+
+// newExporter creates the span exporter named by exporter, and the file it
+// writes to, if any
+func newExporter(exporter string) (sdktrace.SpanExporter, io.Closer, error) {
+	switch {
+	case exporter == "":
+		return nil, nil, fmt.Errorf("no trace exporter given")
+	case exporter == OTLP:
+		spanExporter, err := otlptracegrpc.New(context.Background())
+		return spanExporter, nil, err
+	case strings.HasPrefix(exporter, otlpPrefix):
+		spanExporter, err := otlptracegrpc.New(context.Background(),
+			otlptracegrpc.WithEndpoint(strings.TrimPrefix(exporter, otlpPrefix)),
+			otlptracegrpc.WithInsecure(),
+		)
+		return spanExporter, nil, err
+	case exporter == Stdout:
+		spanExporter, err := stdouttrace.New()
+		return spanExporter, nil, err
+	default:
+		file, err := os.OpenFile(exporter, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		spanExporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return spanExporter, file, nil
+	}
+}
+
+// FlushOnSignal waits for SIGINT or SIGTERM, flushes the spans with
+// shutdown and exits. Spans still buffered would be lost otherwise.
+func FlushOnSignal(shutdown func(context.Context) error) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	sig := <-signals
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+	log.Printf("Received %s, traces flushed", sig)
+	os.Exit(0)
+}
@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,10 +22,65 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Dimensions GetHotelStats can break its counts down by
+type StatsGroup int32
+
+const (
+	StatsGroup_STATS_GROUP_UNSPECIFIED StatsGroup = 0
+	StatsGroup_STATS_GROUP_COUNTRY     StatsGroup = 1
+	StatsGroup_STATS_GROUP_CITY        StatsGroup = 2
+	StatsGroup_STATS_GROUP_STARS       StatsGroup = 3
+)
+
+// Enum value maps for StatsGroup.
+var (
+	StatsGroup_name = map[int32]string{
+		0: "STATS_GROUP_UNSPECIFIED",
+		1: "STATS_GROUP_COUNTRY",
+		2: "STATS_GROUP_CITY",
+		3: "STATS_GROUP_STARS",
+	}
+	StatsGroup_value = map[string]int32{
+		"STATS_GROUP_UNSPECIFIED": 0,
+		"STATS_GROUP_COUNTRY":     1,
+		"STATS_GROUP_CITY":        2,
+		"STATS_GROUP_STARS":       3,
+	}
+)
+
+func (x StatsGroup) Enum() *StatsGroup {
+	p := new(StatsGroup)
+	*p = x
+	return p
+}
+
+func (x StatsGroup) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StatsGroup) Descriptor() protoreflect.EnumDescriptor {
+	return file_data_proto_enumTypes[0].Descriptor()
+}
+
+func (StatsGroup) Type() protoreflect.EnumType {
+	return &file_data_proto_enumTypes[0]
+}
+
+func (x StatsGroup) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StatsGroup.Descriptor instead.
+func (StatsGroup) EnumDescriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{0}
+}
+
 // Stream request with chunk size
 type StreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChunkSize     int32                  `protobuf:"varint,1,opt,name=chunkSize,proto3" json:"chunkSize,omitempty"` // Number of hotels per chunk (default: 100)
+	Filter        *HotelFilter           `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`        // Only stream matching hotels (default: all)
+	Fields        *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=fields,proto3" json:"fields,omitempty"`        // Only send these Hotel fields, e.g. "available", "rooms.rates.amount" (default: all)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +122,565 @@ func (x *StreamRequest) GetChunkSize() int32 {
 	return 0
 }
 
+func (x *StreamRequest) GetFilter() *HotelFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetFields() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// Hotel filter evaluated by the microservice before chunking.
+// Unset fields match every hotel; repeated fields match any of their values.
+type HotelFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CountryCodes  []string               `protobuf:"bytes,1,rep,name=countryCodes,proto3" json:"countryCodes,omitempty"`
+	CityIds       []int32                `protobuf:"varint,2,rep,packed,name=cityIds,proto3" json:"cityIds,omitempty"`
+	MinRating     *float32               `protobuf:"fixed32,3,opt,name=minRating,proto3,oneof" json:"minRating,omitempty"`
+	MaxRating     *float32               `protobuf:"fixed32,4,opt,name=maxRating,proto3,oneof" json:"maxRating,omitempty"`
+	MinPrice      *float64               `protobuf:"fixed64,5,opt,name=minPrice,proto3,oneof" json:"minPrice,omitempty"` // Hotel's maxRate must be at least minPrice
+	MaxPrice      *float64               `protobuf:"fixed64,6,opt,name=maxPrice,proto3,oneof" json:"maxPrice,omitempty"` // Hotel's minRate must be at most maxPrice
+	Available     *bool                  `protobuf:"varint,7,opt,name=available,proto3,oneof" json:"available,omitempty"`
+	Boards        []string               `protobuf:"bytes,8,rep,name=boards,proto3" json:"boards,omitempty"` // Hotel offers at least one of these board codes
+	Tags          []string               `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HotelFilter) Reset() {
+	*x = HotelFilter{}
+	mi := &file_data_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotelFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotelFilter) ProtoMessage() {}
+
+func (x *HotelFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotelFilter.ProtoReflect.Descriptor instead.
+func (*HotelFilter) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HotelFilter) GetCountryCodes() []string {
+	if x != nil {
+		return x.CountryCodes
+	}
+	return nil
+}
+
+func (x *HotelFilter) GetCityIds() []int32 {
+	if x != nil {
+		return x.CityIds
+	}
+	return nil
+}
+
+func (x *HotelFilter) GetMinRating() float32 {
+	if x != nil && x.MinRating != nil {
+		return *x.MinRating
+	}
+	return 0
+}
+
+func (x *HotelFilter) GetMaxRating() float32 {
+	if x != nil && x.MaxRating != nil {
+		return *x.MaxRating
+	}
+	return 0
+}
+
+func (x *HotelFilter) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *HotelFilter) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *HotelFilter) GetAvailable() bool {
+	if x != nil && x.Available != nil {
+		return *x.Available
+	}
+	return false
+}
+
+func (x *HotelFilter) GetBoards() []string {
+	if x != nil {
+		return x.Boards
+	}
+	return nil
+}
+
+func (x *HotelFilter) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// Point lookup of a single hotel by one of its identifiers
+type GetHotelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Key:
+	//
+	//	*GetHotelRequest_HotelId
+	//	*GetHotelRequest_GiataId
+	//	*GetHotelRequest_HUid
+	Key           isGetHotelRequest_Key `protobuf_oneof:"key"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHotelRequest) Reset() {
+	*x = GetHotelRequest{}
+	mi := &file_data_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelRequest) ProtoMessage() {}
+
+func (x *GetHotelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelRequest.ProtoReflect.Descriptor instead.
+func (*GetHotelRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetHotelRequest) GetKey() isGetHotelRequest_Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *GetHotelRequest) GetHotelId() string {
+	if x != nil {
+		if x, ok := x.Key.(*GetHotelRequest_HotelId); ok {
+			return x.HotelId
+		}
+	}
+	return ""
+}
+
+func (x *GetHotelRequest) GetGiataId() int32 {
+	if x != nil {
+		if x, ok := x.Key.(*GetHotelRequest_GiataId); ok {
+			return x.GiataId
+		}
+	}
+	return 0
+}
+
+func (x *GetHotelRequest) GetHUid() int32 {
+	if x != nil {
+		if x, ok := x.Key.(*GetHotelRequest_HUid); ok {
+			return x.HUid
+		}
+	}
+	return 0
+}
+
+type isGetHotelRequest_Key interface {
+	isGetHotelRequest_Key()
+}
+
+type GetHotelRequest_HotelId struct {
+	HotelId string `protobuf:"bytes,1,opt,name=hotelId,proto3,oneof"`
+}
+
+type GetHotelRequest_GiataId struct {
+	GiataId int32 `protobuf:"varint,2,opt,name=giataId,proto3,oneof"`
+}
+
+type GetHotelRequest_HUid struct {
+	HUid int32 `protobuf:"varint,3,opt,name=hUid,proto3,oneof"`
+}
+
+func (*GetHotelRequest_HotelId) isGetHotelRequest_Key() {}
+
+func (*GetHotelRequest_GiataId) isGetHotelRequest_Key() {}
+
+func (*GetHotelRequest_HUid) isGetHotelRequest_Key() {}
+
+// Paginated alternative to GetHotelsStreaming for clients that cannot
+// consume server streams
+type ListHotelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`  // Hotels per page (default: 100, max: 1000)
+	PageToken     string                 `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"` // nextPageToken of the previous page, empty for the first page
+	Filter        *HotelFilter           `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`       // Must not change between pages
+	Fields        *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=fields,proto3" json:"fields,omitempty"`       // Must not change between pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHotelsRequest) Reset() {
+	*x = ListHotelsRequest{}
+	mi := &file_data_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHotelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHotelsRequest) ProtoMessage() {}
+
+func (x *ListHotelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHotelsRequest.ProtoReflect.Descriptor instead.
+func (*ListHotelsRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListHotelsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListHotelsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListHotelsRequest) GetFilter() *HotelFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListHotelsRequest) GetFields() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ListHotelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hotels        []*Hotel               `protobuf:"bytes,1,rep,name=hotels,proto3" json:"hotels,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"` // Empty on the last page
+	TotalSize     int32                  `protobuf:"varint,3,opt,name=totalSize,proto3" json:"totalSize,omitempty"`        // Matching hotels across all pages
+	Metadata      *Metadata              `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`           // Only included in the first page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHotelsResponse) Reset() {
+	*x = ListHotelsResponse{}
+	mi := &file_data_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHotelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHotelsResponse) ProtoMessage() {}
+
+func (x *ListHotelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHotelsResponse.ProtoReflect.Descriptor instead.
+func (*ListHotelsResponse) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListHotelsResponse) GetHotels() []*Hotel {
+	if x != nil {
+		return x.Hotels
+	}
+	return nil
+}
+
+func (x *ListHotelsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListHotelsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *ListHotelsResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Aggregation computed by the microservice instead of streaming the hotels
+type HotelStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *HotelFilter           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`                                // Only count matching hotels (default: all)
+	GroupBy       []StatsGroup           `protobuf:"varint,2,rep,packed,name=groupBy,proto3,enum=data.StatsGroup" json:"groupBy,omitempty"` // Breakdowns to compute (default: all)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HotelStatsRequest) Reset() {
+	*x = HotelStatsRequest{}
+	mi := &file_data_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotelStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotelStatsRequest) ProtoMessage() {}
+
+func (x *HotelStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotelStatsRequest.ProtoReflect.Descriptor instead.
+func (*HotelStatsRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HotelStatsRequest) GetFilter() *HotelFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *HotelStatsRequest) GetGroupBy() []StatsGroup {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+// Counts for one value of a breakdown dimension
+type StatsBucket struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Key             string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`     // countryCode, cityId or whole-star rating; "unknown" when unset
+	Label           string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"` // Country or city name, when known
+	TotalHotels     int32                  `protobuf:"varint,3,opt,name=totalHotels,proto3" json:"totalHotels,omitempty"`
+	AvailableHotels int32                  `protobuf:"varint,4,opt,name=availableHotels,proto3" json:"availableHotels,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StatsBucket) Reset() {
+	*x = StatsBucket{}
+	mi := &file_data_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsBucket) ProtoMessage() {}
+
+func (x *StatsBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsBucket.ProtoReflect.Descriptor instead.
+func (*StatsBucket) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatsBucket) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *StatsBucket) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *StatsBucket) GetTotalHotels() int32 {
+	if x != nil {
+		return x.TotalHotels
+	}
+	return 0
+}
+
+func (x *StatsBucket) GetAvailableHotels() int32 {
+	if x != nil {
+		return x.AvailableHotels
+	}
+	return 0
+}
+
+type HotelStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TotalHotels     int32                  `protobuf:"varint,1,opt,name=totalHotels,proto3" json:"totalHotels,omitempty"`
+	AvailableHotels int32                  `protobuf:"varint,2,opt,name=availableHotels,proto3" json:"availableHotels,omitempty"`
+	ByCountry       []*StatsBucket         `protobuf:"bytes,3,rep,name=byCountry,proto3" json:"byCountry,omitempty"`
+	ByCity          []*StatsBucket         `protobuf:"bytes,4,rep,name=byCity,proto3" json:"byCity,omitempty"`
+	ByStars         []*StatsBucket         `protobuf:"bytes,5,rep,name=byStars,proto3" json:"byStars,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HotelStats) Reset() {
+	*x = HotelStats{}
+	mi := &file_data_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotelStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotelStats) ProtoMessage() {}
+
+func (x *HotelStats) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotelStats.ProtoReflect.Descriptor instead.
+func (*HotelStats) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HotelStats) GetTotalHotels() int32 {
+	if x != nil {
+		return x.TotalHotels
+	}
+	return 0
+}
+
+func (x *HotelStats) GetAvailableHotels() int32 {
+	if x != nil {
+		return x.AvailableHotels
+	}
+	return 0
+}
+
+func (x *HotelStats) GetByCountry() []*StatsBucket {
+	if x != nil {
+		return x.ByCountry
+	}
+	return nil
+}
+
+func (x *HotelStats) GetByCity() []*StatsBucket {
+	if x != nil {
+		return x.ByCity
+	}
+	return nil
+}
+
+func (x *HotelStats) GetByStars() []*StatsBucket {
+	if x != nil {
+		return x.ByStars
+	}
+	return nil
+}
+
 // Hotel message matching the JSON structure
 type Hotel struct {
 	state                    protoimpl.MessageState `protogen:"open.v1"`
@@ -118,7 +733,7 @@ type Hotel struct {
 
 func (x *Hotel) Reset() {
 	*x = Hotel{}
-	mi := &file_data_proto_msgTypes[1]
+	mi := &file_data_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -130,7 +745,7 @@ func (x *Hotel) String() string {
 func (*Hotel) ProtoMessage() {}
 
 func (x *Hotel) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[1]
+	mi := &file_data_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -143,7 +758,7 @@ func (x *Hotel) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Hotel.ProtoReflect.Descriptor instead.
 func (*Hotel) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{1}
+	return file_data_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Hotel) GetSupplierId() int32 {
@@ -464,7 +1079,7 @@ type Room struct {
 
 func (x *Room) Reset() {
 	*x = Room{}
-	mi := &file_data_proto_msgTypes[2]
+	mi := &file_data_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -476,7 +1091,7 @@ func (x *Room) String() string {
 func (*Room) ProtoMessage() {}
 
 func (x *Room) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[2]
+	mi := &file_data_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,7 +1104,7 @@ func (x *Room) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Room.ProtoReflect.Descriptor instead.
 func (*Room) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{2}
+	return file_data_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Room) GetCode() string {
@@ -589,7 +1204,7 @@ type Rate struct {
 
 func (x *Rate) Reset() {
 	*x = Rate{}
-	mi := &file_data_proto_msgTypes[3]
+	mi := &file_data_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -601,7 +1216,7 @@ func (x *Rate) String() string {
 func (*Rate) ProtoMessage() {}
 
 func (x *Rate) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[3]
+	mi := &file_data_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -614,7 +1229,7 @@ func (x *Rate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Rate.ProtoReflect.Descriptor instead.
 func (*Rate) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{3}
+	return file_data_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Rate) GetRateKey() string {
@@ -812,7 +1427,7 @@ type CancellationPolicy struct {
 
 func (x *CancellationPolicy) Reset() {
 	*x = CancellationPolicy{}
-	mi := &file_data_proto_msgTypes[4]
+	mi := &file_data_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -824,7 +1439,7 @@ func (x *CancellationPolicy) String() string {
 func (*CancellationPolicy) ProtoMessage() {}
 
 func (x *CancellationPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[4]
+	mi := &file_data_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -837,7 +1452,7 @@ func (x *CancellationPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancellationPolicy.ProtoReflect.Descriptor instead.
 func (*CancellationPolicy) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{4}
+	return file_data_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CancellationPolicy) GetAmount() float64 {
@@ -886,7 +1501,7 @@ type Offer struct {
 
 func (x *Offer) Reset() {
 	*x = Offer{}
-	mi := &file_data_proto_msgTypes[5]
+	mi := &file_data_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -898,7 +1513,7 @@ func (x *Offer) String() string {
 func (*Offer) ProtoMessage() {}
 
 func (x *Offer) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[5]
+	mi := &file_data_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -911,7 +1526,7 @@ func (x *Offer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Offer.ProtoReflect.Descriptor instead.
 func (*Offer) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{5}
+	return file_data_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Offer) GetAmount() float64 {
@@ -946,7 +1561,7 @@ type Promotion struct {
 
 func (x *Promotion) Reset() {
 	*x = Promotion{}
-	mi := &file_data_proto_msgTypes[6]
+	mi := &file_data_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -958,7 +1573,7 @@ func (x *Promotion) String() string {
 func (*Promotion) ProtoMessage() {}
 
 func (x *Promotion) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[6]
+	mi := &file_data_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -971,7 +1586,7 @@ func (x *Promotion) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Promotion.ProtoReflect.Descriptor instead.
 func (*Promotion) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{6}
+	return file_data_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Promotion) GetRemark() string {
@@ -1007,7 +1622,7 @@ type Supplement struct {
 
 func (x *Supplement) Reset() {
 	*x = Supplement{}
-	mi := &file_data_proto_msgTypes[7]
+	mi := &file_data_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1019,7 +1634,7 @@ func (x *Supplement) String() string {
 func (*Supplement) ProtoMessage() {}
 
 func (x *Supplement) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[7]
+	mi := &file_data_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1032,7 +1647,7 @@ func (x *Supplement) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Supplement.ProtoReflect.Descriptor instead.
 func (*Supplement) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{7}
+	return file_data_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Supplement) GetName() string {
@@ -1076,7 +1691,7 @@ type Tax struct {
 
 func (x *Tax) Reset() {
 	*x = Tax{}
-	mi := &file_data_proto_msgTypes[8]
+	mi := &file_data_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1088,7 +1703,7 @@ func (x *Tax) String() string {
 func (*Tax) ProtoMessage() {}
 
 func (x *Tax) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[8]
+	mi := &file_data_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1101,7 +1716,7 @@ func (x *Tax) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Tax.ProtoReflect.Descriptor instead.
 func (*Tax) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{8}
+	return file_data_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *Tax) GetName() string {
@@ -1149,7 +1764,7 @@ type Neighborhood struct {
 
 func (x *Neighborhood) Reset() {
 	*x = Neighborhood{}
-	mi := &file_data_proto_msgTypes[9]
+	mi := &file_data_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1161,7 +1776,7 @@ func (x *Neighborhood) String() string {
 func (*Neighborhood) ProtoMessage() {}
 
 func (x *Neighborhood) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[9]
+	mi := &file_data_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1174,7 +1789,7 @@ func (x *Neighborhood) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Neighborhood.ProtoReflect.Descriptor instead.
 func (*Neighborhood) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{9}
+	return file_data_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *Neighborhood) GetName() string {
@@ -1202,7 +1817,7 @@ type Review struct {
 
 func (x *Review) Reset() {
 	*x = Review{}
-	mi := &file_data_proto_msgTypes[10]
+	mi := &file_data_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1214,7 +1829,7 @@ func (x *Review) String() string {
 func (*Review) ProtoMessage() {}
 
 func (x *Review) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[10]
+	mi := &file_data_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1227,7 +1842,7 @@ func (x *Review) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Review.ProtoReflect.Descriptor instead.
 func (*Review) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{10}
+	return file_data_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *Review) GetScore() float64 {
@@ -1265,7 +1880,7 @@ type HotelReview struct {
 
 func (x *HotelReview) Reset() {
 	*x = HotelReview{}
-	mi := &file_data_proto_msgTypes[11]
+	mi := &file_data_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1277,7 +1892,7 @@ func (x *HotelReview) String() string {
 func (*HotelReview) ProtoMessage() {}
 
 func (x *HotelReview) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[11]
+	mi := &file_data_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1290,7 +1905,7 @@ func (x *HotelReview) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HotelReview.ProtoReflect.Descriptor instead.
 func (*HotelReview) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{11}
+	return file_data_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *HotelReview) GetId() string {
@@ -1349,7 +1964,7 @@ type Metadata struct {
 
 func (x *Metadata) Reset() {
 	*x = Metadata{}
-	mi := &file_data_proto_msgTypes[12]
+	mi := &file_data_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1361,7 +1976,7 @@ func (x *Metadata) String() string {
 func (*Metadata) ProtoMessage() {}
 
 func (x *Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[12]
+	mi := &file_data_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1374,7 +1989,7 @@ func (x *Metadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
 func (*Metadata) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{12}
+	return file_data_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *Metadata) GetGeneratedAt() string {
@@ -1426,7 +2041,7 @@ type HotelChunk struct {
 
 func (x *HotelChunk) Reset() {
 	*x = HotelChunk{}
-	mi := &file_data_proto_msgTypes[13]
+	mi := &file_data_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1438,7 +2053,7 @@ func (x *HotelChunk) String() string {
 func (*HotelChunk) ProtoMessage() {}
 
 func (x *HotelChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[13]
+	mi := &file_data_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1451,7 +2066,7 @@ func (x *HotelChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HotelChunk.ProtoReflect.Descriptor instead.
 func (*HotelChunk) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{13}
+	return file_data_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *HotelChunk) GetHotels() []*Hotel {
@@ -1494,9 +2109,59 @@ var File_data_proto protoreflect.FileDescriptor
 const file_data_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"data.proto\x12\x04data\"-\n" +
+	"data.proto\x12\x04data\x1a google/protobuf/field_mask.proto\"\x8c\x01\n" +
 	"\rStreamRequest\x12\x1c\n" +
-	"\tchunkSize\x18\x01 \x01(\x05R\tchunkSize\"\xa7\x10\n" +
+	"\tchunkSize\x18\x01 \x01(\x05R\tchunkSize\x12)\n" +
+	"\x06filter\x18\x02 \x01(\v2\x11.data.HotelFilterR\x06filter\x122\n" +
+	"\x06fields\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\x06fields\"\xe6\x02\n" +
+	"\vHotelFilter\x12\"\n" +
+	"\fcountryCodes\x18\x01 \x03(\tR\fcountryCodes\x12\x18\n" +
+	"\acityIds\x18\x02 \x03(\x05R\acityIds\x12!\n" +
+	"\tminRating\x18\x03 \x01(\x02H\x00R\tminRating\x88\x01\x01\x12!\n" +
+	"\tmaxRating\x18\x04 \x01(\x02H\x01R\tmaxRating\x88\x01\x01\x12\x1f\n" +
+	"\bminPrice\x18\x05 \x01(\x01H\x02R\bminPrice\x88\x01\x01\x12\x1f\n" +
+	"\bmaxPrice\x18\x06 \x01(\x01H\x03R\bmaxPrice\x88\x01\x01\x12!\n" +
+	"\tavailable\x18\a \x01(\bH\x04R\tavailable\x88\x01\x01\x12\x16\n" +
+	"\x06boards\x18\b \x03(\tR\x06boards\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tagsB\f\n" +
+	"\n" +
+	"_minRatingB\f\n" +
+	"\n" +
+	"_maxRatingB\v\n" +
+	"\t_minPriceB\v\n" +
+	"\t_maxPriceB\f\n" +
+	"\n" +
+	"_available\"f\n" +
+	"\x0fGetHotelRequest\x12\x1a\n" +
+	"\ahotelId\x18\x01 \x01(\tH\x00R\ahotelId\x12\x1a\n" +
+	"\agiataId\x18\x02 \x01(\x05H\x00R\agiataId\x12\x14\n" +
+	"\x04hUid\x18\x03 \x01(\x05H\x00R\x04hUidB\x05\n" +
+	"\x03key\"\xac\x01\n" +
+	"\x11ListHotelsRequest\x12\x1a\n" +
+	"\bpageSize\x18\x01 \x01(\x05R\bpageSize\x12\x1c\n" +
+	"\tpageToken\x18\x02 \x01(\tR\tpageToken\x12)\n" +
+	"\x06filter\x18\x03 \x01(\v2\x11.data.HotelFilterR\x06filter\x122\n" +
+	"\x06fields\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\x06fields\"\xa9\x01\n" +
+	"\x12ListHotelsResponse\x12#\n" +
+	"\x06hotels\x18\x01 \x03(\v2\v.data.HotelR\x06hotels\x12$\n" +
+	"\rnextPageToken\x18\x02 \x01(\tR\rnextPageToken\x12\x1c\n" +
+	"\ttotalSize\x18\x03 \x01(\x05R\ttotalSize\x12*\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x0e.data.MetadataR\bmetadata\"j\n" +
+	"\x11HotelStatsRequest\x12)\n" +
+	"\x06filter\x18\x01 \x01(\v2\x11.data.HotelFilterR\x06filter\x12*\n" +
+	"\agroupBy\x18\x02 \x03(\x0e2\x10.data.StatsGroupR\agroupBy\"\x81\x01\n" +
+	"\vStatsBucket\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12 \n" +
+	"\vtotalHotels\x18\x03 \x01(\x05R\vtotalHotels\x12(\n" +
+	"\x0favailableHotels\x18\x04 \x01(\x05R\x0favailableHotels\"\xe1\x01\n" +
+	"\n" +
+	"HotelStats\x12 \n" +
+	"\vtotalHotels\x18\x01 \x01(\x05R\vtotalHotels\x12(\n" +
+	"\x0favailableHotels\x18\x02 \x01(\x05R\x0favailableHotels\x12/\n" +
+	"\tbyCountry\x18\x03 \x03(\v2\x11.data.StatsBucketR\tbyCountry\x12)\n" +
+	"\x06byCity\x18\x04 \x03(\v2\x11.data.StatsBucketR\x06byCity\x12+\n" +
+	"\abyStars\x18\x05 \x03(\v2\x11.data.StatsBucketR\abyStars\"\xa7\x10\n" +
 	"\x05Hotel\x12#\n" +
 	"\n" +
 	"supplierId\x18\x01 \x01(\x05H\x00R\n" +
@@ -1755,9 +2420,19 @@ const file_data_proto_rawDesc = "" +
 	"chunkIndex\x12 \n" +
 	"\vtotalChunks\x18\x03 \x01(\x05R\vtotalChunks\x12\x16\n" +
 	"\x06isLast\x18\x04 \x01(\bR\x06isLast\x12*\n" +
-	"\bmetadata\x18\x05 \x01(\v2\x0e.data.MetadataR\bmetadata2L\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x0e.data.MetadataR\bmetadata*o\n" +
+	"\n" +
+	"StatsGroup\x12\x1b\n" +
+	"\x17STATS_GROUP_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13STATS_GROUP_COUNTRY\x10\x01\x12\x14\n" +
+	"\x10STATS_GROUP_CITY\x10\x02\x12\x15\n" +
+	"\x11STATS_GROUP_STARS\x10\x032\xf9\x01\n" +
 	"\vDataService\x12=\n" +
-	"\x12GetHotelsStreaming\x12\x13.data.StreamRequest\x1a\x10.data.HotelChunk0\x01B\tZ\a./protob\x06proto3"
+	"\x12GetHotelsStreaming\x12\x13.data.StreamRequest\x1a\x10.data.HotelChunk0\x01\x12.\n" +
+	"\bGetHotel\x12\x15.data.GetHotelRequest\x1a\v.data.Hotel\x12:\n" +
+	"\rGetHotelStats\x12\x17.data.HotelStatsRequest\x1a\x10.data.HotelStats\x12?\n" +
+	"\n" +
+	"ListHotels\x12\x17.data.ListHotelsRequest\x1a\x18.data.ListHotelsResponseB\x14Z\x12grpc-vs-http/protob\x06proto3"
 
 var (
 	file_data_proto_rawDescOnce sync.Once
@@ -1771,52 +2446,79 @@ func file_data_proto_rawDescGZIP() []byte {
 	return file_data_proto_rawDescData
 }
 
-var file_data_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_data_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_data_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_data_proto_goTypes = []any{
-	(*StreamRequest)(nil),      // 0: data.StreamRequest
-	(*Hotel)(nil),              // 1: data.Hotel
-	(*Room)(nil),               // 2: data.Room
-	(*Rate)(nil),               // 3: data.Rate
-	(*CancellationPolicy)(nil), // 4: data.CancellationPolicy
-	(*Offer)(nil),              // 5: data.Offer
-	(*Promotion)(nil),          // 6: data.Promotion
-	(*Supplement)(nil),         // 7: data.Supplement
-	(*Tax)(nil),                // 8: data.Tax
-	(*Neighborhood)(nil),       // 9: data.Neighborhood
-	(*Review)(nil),             // 10: data.Review
-	(*HotelReview)(nil),        // 11: data.HotelReview
-	(*Metadata)(nil),           // 12: data.Metadata
-	(*HotelChunk)(nil),         // 13: data.HotelChunk
-	nil,                        // 14: data.Hotel.DistancesEntry
-	nil,                        // 15: data.Hotel.StrengthEntry
-	nil,                        // 16: data.Hotel.ReviewsSubratingsAverageEntry
-	nil,                        // 17: data.HotelReview.SubratingsEntry
+	(StatsGroup)(0),               // 0: data.StatsGroup
+	(*StreamRequest)(nil),         // 1: data.StreamRequest
+	(*HotelFilter)(nil),           // 2: data.HotelFilter
+	(*GetHotelRequest)(nil),       // 3: data.GetHotelRequest
+	(*ListHotelsRequest)(nil),     // 4: data.ListHotelsRequest
+	(*ListHotelsResponse)(nil),    // 5: data.ListHotelsResponse
+	(*HotelStatsRequest)(nil),     // 6: data.HotelStatsRequest
+	(*StatsBucket)(nil),           // 7: data.StatsBucket
+	(*HotelStats)(nil),            // 8: data.HotelStats
+	(*Hotel)(nil),                 // 9: data.Hotel
+	(*Room)(nil),                  // 10: data.Room
+	(*Rate)(nil),                  // 11: data.Rate
+	(*CancellationPolicy)(nil),    // 12: data.CancellationPolicy
+	(*Offer)(nil),                 // 13: data.Offer
+	(*Promotion)(nil),             // 14: data.Promotion
+	(*Supplement)(nil),            // 15: data.Supplement
+	(*Tax)(nil),                   // 16: data.Tax
+	(*Neighborhood)(nil),          // 17: data.Neighborhood
+	(*Review)(nil),                // 18: data.Review
+	(*HotelReview)(nil),           // 19: data.HotelReview
+	(*Metadata)(nil),              // 20: data.Metadata
+	(*HotelChunk)(nil),            // 21: data.HotelChunk
+	nil,                           // 22: data.Hotel.DistancesEntry
+	nil,                           // 23: data.Hotel.StrengthEntry
+	nil,                           // 24: data.Hotel.ReviewsSubratingsAverageEntry
+	nil,                           // 25: data.HotelReview.SubratingsEntry
+	(*fieldmaskpb.FieldMask)(nil), // 26: google.protobuf.FieldMask
 }
 var file_data_proto_depIdxs = []int32{
-	2,  // 0: data.Hotel.rooms:type_name -> data.Room
-	7,  // 1: data.Hotel.supplements:type_name -> data.Supplement
-	14, // 2: data.Hotel.distances:type_name -> data.Hotel.DistancesEntry
-	9,  // 3: data.Hotel.neighborhood:type_name -> data.Neighborhood
-	15, // 4: data.Hotel.strength:type_name -> data.Hotel.StrengthEntry
-	10, // 5: data.Hotel.review:type_name -> data.Review
-	16, // 6: data.Hotel.reviewsSubratingsAverage:type_name -> data.Hotel.ReviewsSubratingsAverageEntry
-	11, // 7: data.Hotel.reviews:type_name -> data.HotelReview
-	3,  // 8: data.Room.rates:type_name -> data.Rate
-	4,  // 9: data.Rate.cancellationPolicies:type_name -> data.CancellationPolicy
-	5,  // 10: data.Rate.offers:type_name -> data.Offer
-	6,  // 11: data.Rate.promotions:type_name -> data.Promotion
-	7,  // 12: data.Rate.supplements:type_name -> data.Supplement
-	8,  // 13: data.Rate.taxes:type_name -> data.Tax
-	17, // 14: data.HotelReview.subratings:type_name -> data.HotelReview.SubratingsEntry
-	1,  // 15: data.HotelChunk.hotels:type_name -> data.Hotel
-	12, // 16: data.HotelChunk.metadata:type_name -> data.Metadata
-	0,  // 17: data.DataService.GetHotelsStreaming:input_type -> data.StreamRequest
-	13, // 18: data.DataService.GetHotelsStreaming:output_type -> data.HotelChunk
-	18, // [18:19] is the sub-list for method output_type
-	17, // [17:18] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	2,  // 0: data.StreamRequest.filter:type_name -> data.HotelFilter
+	26, // 1: data.StreamRequest.fields:type_name -> google.protobuf.FieldMask
+	2,  // 2: data.ListHotelsRequest.filter:type_name -> data.HotelFilter
+	26, // 3: data.ListHotelsRequest.fields:type_name -> google.protobuf.FieldMask
+	9,  // 4: data.ListHotelsResponse.hotels:type_name -> data.Hotel
+	20, // 5: data.ListHotelsResponse.metadata:type_name -> data.Metadata
+	2,  // 6: data.HotelStatsRequest.filter:type_name -> data.HotelFilter
+	0,  // 7: data.HotelStatsRequest.groupBy:type_name -> data.StatsGroup
+	7,  // 8: data.HotelStats.byCountry:type_name -> data.StatsBucket
+	7,  // 9: data.HotelStats.byCity:type_name -> data.StatsBucket
+	7,  // 10: data.HotelStats.byStars:type_name -> data.StatsBucket
+	10, // 11: data.Hotel.rooms:type_name -> data.Room
+	15, // 12: data.Hotel.supplements:type_name -> data.Supplement
+	22, // 13: data.Hotel.distances:type_name -> data.Hotel.DistancesEntry
+	17, // 14: data.Hotel.neighborhood:type_name -> data.Neighborhood
+	23, // 15: data.Hotel.strength:type_name -> data.Hotel.StrengthEntry
+	18, // 16: data.Hotel.review:type_name -> data.Review
+	24, // 17: data.Hotel.reviewsSubratingsAverage:type_name -> data.Hotel.ReviewsSubratingsAverageEntry
+	19, // 18: data.Hotel.reviews:type_name -> data.HotelReview
+	11, // 19: data.Room.rates:type_name -> data.Rate
+	12, // 20: data.Rate.cancellationPolicies:type_name -> data.CancellationPolicy
+	13, // 21: data.Rate.offers:type_name -> data.Offer
+	14, // 22: data.Rate.promotions:type_name -> data.Promotion
+	15, // 23: data.Rate.supplements:type_name -> data.Supplement
+	16, // 24: data.Rate.taxes:type_name -> data.Tax
+	25, // 25: data.HotelReview.subratings:type_name -> data.HotelReview.SubratingsEntry
+	9,  // 26: data.HotelChunk.hotels:type_name -> data.Hotel
+	20, // 27: data.HotelChunk.metadata:type_name -> data.Metadata
+	1,  // 28: data.DataService.GetHotelsStreaming:input_type -> data.StreamRequest
+	3,  // 29: data.DataService.GetHotel:input_type -> data.GetHotelRequest
+	6,  // 30: data.DataService.GetHotelStats:input_type -> data.HotelStatsRequest
+	4,  // 31: data.DataService.ListHotels:input_type -> data.ListHotelsRequest
+	21, // 32: data.DataService.GetHotelsStreaming:output_type -> data.HotelChunk
+	9,  // 33: data.DataService.GetHotel:output_type -> data.Hotel
+	8,  // 34: data.DataService.GetHotelStats:output_type -> data.HotelStats
+	5,  // 35: data.DataService.ListHotels:output_type -> data.ListHotelsResponse
+	32, // [32:36] is the sub-list for method output_type
+	28, // [28:32] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_data_proto_init() }
@@ -1825,25 +2527,32 @@ func file_data_proto_init() {
 		return
 	}
 	file_data_proto_msgTypes[1].OneofWrappers = []any{}
-	file_data_proto_msgTypes[2].OneofWrappers = []any{}
-	file_data_proto_msgTypes[3].OneofWrappers = []any{}
-	file_data_proto_msgTypes[4].OneofWrappers = []any{}
-	file_data_proto_msgTypes[5].OneofWrappers = []any{}
-	file_data_proto_msgTypes[6].OneofWrappers = []any{}
-	file_data_proto_msgTypes[7].OneofWrappers = []any{}
+	file_data_proto_msgTypes[2].OneofWrappers = []any{
+		(*GetHotelRequest_HotelId)(nil),
+		(*GetHotelRequest_GiataId)(nil),
+		(*GetHotelRequest_HUid)(nil),
+	}
 	file_data_proto_msgTypes[8].OneofWrappers = []any{}
+	file_data_proto_msgTypes[9].OneofWrappers = []any{}
+	file_data_proto_msgTypes[10].OneofWrappers = []any{}
+	file_data_proto_msgTypes[11].OneofWrappers = []any{}
+	file_data_proto_msgTypes[12].OneofWrappers = []any{}
+	file_data_proto_msgTypes[13].OneofWrappers = []any{}
+	file_data_proto_msgTypes[14].OneofWrappers = []any{}
+	file_data_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_data_proto_rawDesc), len(file_data_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   18,
+			NumEnums:      1,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_data_proto_goTypes,
 		DependencyIndexes: file_data_proto_depIdxs,
+		EnumInfos:         file_data_proto_enumTypes,
 		MessageInfos:      file_data_proto_msgTypes,
 	}.Build()
 	File_data_proto = out.File
@@ -20,6 +20,9 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	DataService_GetHotelsStreaming_FullMethodName = "/data.DataService/GetHotelsStreaming"
+	DataService_GetHotel_FullMethodName           = "/data.DataService/GetHotel"
+	DataService_GetHotelStats_FullMethodName      = "/data.DataService/GetHotelStats"
+	DataService_ListHotels_FullMethodName         = "/data.DataService/ListHotels"
 )
 
 // DataServiceClient is the client API for DataService service.
@@ -29,6 +32,9 @@ const (
 // Data service definition with streaming support
 type DataServiceClient interface {
 	GetHotelsStreaming(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[HotelChunk], error)
+	GetHotel(ctx context.Context, in *GetHotelRequest, opts ...grpc.CallOption) (*Hotel, error)
+	GetHotelStats(ctx context.Context, in *HotelStatsRequest, opts ...grpc.CallOption) (*HotelStats, error)
+	ListHotels(ctx context.Context, in *ListHotelsRequest, opts ...grpc.CallOption) (*ListHotelsResponse, error)
 }
 
 type dataServiceClient struct {
@@ -58,6 +64,36 @@ func (c *dataServiceClient) GetHotelsStreaming(ctx context.Context, in *StreamRe
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DataService_GetHotelsStreamingClient = grpc.ServerStreamingClient[HotelChunk]
 
+func (c *dataServiceClient) GetHotel(ctx context.Context, in *GetHotelRequest, opts ...grpc.CallOption) (*Hotel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Hotel)
+	err := c.cc.Invoke(ctx, DataService_GetHotel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) GetHotelStats(ctx context.Context, in *HotelStatsRequest, opts ...grpc.CallOption) (*HotelStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HotelStats)
+	err := c.cc.Invoke(ctx, DataService_GetHotelStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) ListHotels(ctx context.Context, in *ListHotelsRequest, opts ...grpc.CallOption) (*ListHotelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHotelsResponse)
+	err := c.cc.Invoke(ctx, DataService_ListHotels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataServiceServer is the server API for DataService service.
 // All implementations must embed UnimplementedDataServiceServer
 // for forward compatibility.
@@ -65,6 +101,9 @@ type DataService_GetHotelsStreamingClient = grpc.ServerStreamingClient[HotelChun
 // Data service definition with streaming support
 type DataServiceServer interface {
 	GetHotelsStreaming(*StreamRequest, grpc.ServerStreamingServer[HotelChunk]) error
+	GetHotel(context.Context, *GetHotelRequest) (*Hotel, error)
+	GetHotelStats(context.Context, *HotelStatsRequest) (*HotelStats, error)
+	ListHotels(context.Context, *ListHotelsRequest) (*ListHotelsResponse, error)
 	mustEmbedUnimplementedDataServiceServer()
 }
 
@@ -78,6 +117,15 @@ type UnimplementedDataServiceServer struct{}
 func (UnimplementedDataServiceServer) GetHotelsStreaming(*StreamRequest, grpc.ServerStreamingServer[HotelChunk]) error {
 	return status.Errorf(codes.Unimplemented, "method GetHotelsStreaming not implemented")
 }
+func (UnimplementedDataServiceServer) GetHotel(context.Context, *GetHotelRequest) (*Hotel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHotel not implemented")
+}
+func (UnimplementedDataServiceServer) GetHotelStats(context.Context, *HotelStatsRequest) (*HotelStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHotelStats not implemented")
+}
+func (UnimplementedDataServiceServer) ListHotels(context.Context, *ListHotelsRequest) (*ListHotelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHotels not implemented")
+}
 func (UnimplementedDataServiceServer) mustEmbedUnimplementedDataServiceServer() {}
 func (UnimplementedDataServiceServer) testEmbeddedByValue()                     {}
 
@@ -110,13 +158,80 @@ func _DataService_GetHotelsStreaming_Handler(srv interface{}, stream grpc.Server
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DataService_GetHotelsStreamingServer = grpc.ServerStreamingServer[HotelChunk]
 
+func _DataService_GetHotel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHotelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).GetHotel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_GetHotel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).GetHotel(ctx, req.(*GetHotelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_GetHotelStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HotelStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).GetHotelStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_GetHotelStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).GetHotelStats(ctx, req.(*HotelStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_ListHotels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHotelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ListHotels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ListHotels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ListHotels(ctx, req.(*ListHotelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DataService_ServiceDesc is the grpc.ServiceDesc for DataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var DataService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "data.DataService",
 	HandlerType: (*DataServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHotel",
+			Handler:    _DataService_GetHotel_Handler,
+		},
+		{
+			MethodName: "GetHotelStats",
+			Handler:    _DataService_GetHotelStats_Handler,
+		},
+		{
+			MethodName: "ListHotels",
+			Handler:    _DataService_ListHotels_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "GetHotelsStreaming",
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: data.proto
+
+package protoconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	proto "grpc-vs-http/proto"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// DataServiceName is the fully-qualified name of the DataService service.
+	DataServiceName = "data.DataService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DataServiceGetHotelsStreamingProcedure is the fully-qualified name of the DataService's
+	// GetHotelsStreaming RPC.
+	DataServiceGetHotelsStreamingProcedure = "/data.DataService/GetHotelsStreaming"
+	// DataServiceGetHotelProcedure is the fully-qualified name of the DataService's GetHotel RPC.
+	DataServiceGetHotelProcedure = "/data.DataService/GetHotel"
+	// DataServiceGetHotelStatsProcedure is the fully-qualified name of the DataService's GetHotelStats
+	// RPC.
+	DataServiceGetHotelStatsProcedure = "/data.DataService/GetHotelStats"
+	// DataServiceListHotelsProcedure is the fully-qualified name of the DataService's ListHotels RPC.
+	DataServiceListHotelsProcedure = "/data.DataService/ListHotels"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	dataServiceServiceDescriptor                  = proto.File_data_proto.Services().ByName("DataService")
+	dataServiceGetHotelsStreamingMethodDescriptor = dataServiceServiceDescriptor.Methods().ByName("GetHotelsStreaming")
+	dataServiceGetHotelMethodDescriptor           = dataServiceServiceDescriptor.Methods().ByName("GetHotel")
+	dataServiceGetHotelStatsMethodDescriptor      = dataServiceServiceDescriptor.Methods().ByName("GetHotelStats")
+	dataServiceListHotelsMethodDescriptor         = dataServiceServiceDescriptor.Methods().ByName("ListHotels")
+)
+
+// DataServiceClient is a client for the data.DataService service.
+type DataServiceClient interface {
+	GetHotelsStreaming(context.Context, *connect.Request[proto.StreamRequest]) (*connect.ServerStreamForClient[proto.HotelChunk], error)
+	GetHotel(context.Context, *connect.Request[proto.GetHotelRequest]) (*connect.Response[proto.Hotel], error)
+	GetHotelStats(context.Context, *connect.Request[proto.HotelStatsRequest]) (*connect.Response[proto.HotelStats], error)
+	ListHotels(context.Context, *connect.Request[proto.ListHotelsRequest]) (*connect.Response[proto.ListHotelsResponse], error)
+}
+
+// NewDataServiceClient constructs a client for the data.DataService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewDataServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) DataServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &dataServiceClient{
+		getHotelsStreaming: connect.NewClient[proto.StreamRequest, proto.HotelChunk](
+			httpClient,
+			baseURL+DataServiceGetHotelsStreamingProcedure,
+			connect.WithSchema(dataServiceGetHotelsStreamingMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getHotel: connect.NewClient[proto.GetHotelRequest, proto.Hotel](
+			httpClient,
+			baseURL+DataServiceGetHotelProcedure,
+			connect.WithSchema(dataServiceGetHotelMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getHotelStats: connect.NewClient[proto.HotelStatsRequest, proto.HotelStats](
+			httpClient,
+			baseURL+DataServiceGetHotelStatsProcedure,
+			connect.WithSchema(dataServiceGetHotelStatsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listHotels: connect.NewClient[proto.ListHotelsRequest, proto.ListHotelsResponse](
+			httpClient,
+			baseURL+DataServiceListHotelsProcedure,
+			connect.WithSchema(dataServiceListHotelsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// dataServiceClient implements DataServiceClient.
+type dataServiceClient struct {
+	getHotelsStreaming *connect.Client[proto.StreamRequest, proto.HotelChunk]
+	getHotel           *connect.Client[proto.GetHotelRequest, proto.Hotel]
+	getHotelStats      *connect.Client[proto.HotelStatsRequest, proto.HotelStats]
+	listHotels         *connect.Client[proto.ListHotelsRequest, proto.ListHotelsResponse]
+}
+
+// GetHotelsStreaming calls data.DataService.GetHotelsStreaming.
+func (c *dataServiceClient) GetHotelsStreaming(ctx context.Context, req *connect.Request[proto.StreamRequest]) (*connect.ServerStreamForClient[proto.HotelChunk], error) {
+	return c.getHotelsStreaming.CallServerStream(ctx, req)
+}
+
+// GetHotel calls data.DataService.GetHotel.
+func (c *dataServiceClient) GetHotel(ctx context.Context, req *connect.Request[proto.GetHotelRequest]) (*connect.Response[proto.Hotel], error) {
+	return c.getHotel.CallUnary(ctx, req)
+}
+
+// GetHotelStats calls data.DataService.GetHotelStats.
+func (c *dataServiceClient) GetHotelStats(ctx context.Context, req *connect.Request[proto.HotelStatsRequest]) (*connect.Response[proto.HotelStats], error) {
+	return c.getHotelStats.CallUnary(ctx, req)
+}
+
+// ListHotels calls data.DataService.ListHotels.
+func (c *dataServiceClient) ListHotels(ctx context.Context, req *connect.Request[proto.ListHotelsRequest]) (*connect.Response[proto.ListHotelsResponse], error) {
+	return c.listHotels.CallUnary(ctx, req)
+}
+
+// DataServiceHandler is an implementation of the data.DataService service.
+type DataServiceHandler interface {
+	GetHotelsStreaming(context.Context, *connect.Request[proto.StreamRequest], *connect.ServerStream[proto.HotelChunk]) error
+	GetHotel(context.Context, *connect.Request[proto.GetHotelRequest]) (*connect.Response[proto.Hotel], error)
+	GetHotelStats(context.Context, *connect.Request[proto.HotelStatsRequest]) (*connect.Response[proto.HotelStats], error)
+	ListHotels(context.Context, *connect.Request[proto.ListHotelsRequest]) (*connect.Response[proto.ListHotelsResponse], error)
+}
+
+// NewDataServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewDataServiceHandler(svc DataServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	dataServiceGetHotelsStreamingHandler := connect.NewServerStreamHandler(
+		DataServiceGetHotelsStreamingProcedure,
+		svc.GetHotelsStreaming,
+		connect.WithSchema(dataServiceGetHotelsStreamingMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	dataServiceGetHotelHandler := connect.NewUnaryHandler(
+		DataServiceGetHotelProcedure,
+		svc.GetHotel,
+		connect.WithSchema(dataServiceGetHotelMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	dataServiceGetHotelStatsHandler := connect.NewUnaryHandler(
+		DataServiceGetHotelStatsProcedure,
+		svc.GetHotelStats,
+		connect.WithSchema(dataServiceGetHotelStatsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	dataServiceListHotelsHandler := connect.NewUnaryHandler(
+		DataServiceListHotelsProcedure,
+		svc.ListHotels,
+		connect.WithSchema(dataServiceListHotelsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/data.DataService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DataServiceGetHotelsStreamingProcedure:
+			dataServiceGetHotelsStreamingHandler.ServeHTTP(w, r)
+		case DataServiceGetHotelProcedure:
+			dataServiceGetHotelHandler.ServeHTTP(w, r)
+		case DataServiceGetHotelStatsProcedure:
+			dataServiceGetHotelStatsHandler.ServeHTTP(w, r)
+		case DataServiceListHotelsProcedure:
+			dataServiceListHotelsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedDataServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedDataServiceHandler struct{}
+
+func (UnimplementedDataServiceHandler) GetHotelsStreaming(context.Context, *connect.Request[proto.StreamRequest], *connect.ServerStream[proto.HotelChunk]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("data.DataService.GetHotelsStreaming is not implemented"))
+}
+
+func (UnimplementedDataServiceHandler) GetHotel(context.Context, *connect.Request[proto.GetHotelRequest]) (*connect.Response[proto.Hotel], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("data.DataService.GetHotel is not implemented"))
+}
+
+func (UnimplementedDataServiceHandler) GetHotelStats(context.Context, *connect.Request[proto.HotelStatsRequest]) (*connect.Response[proto.HotelStats], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("data.DataService.GetHotelStats is not implemented"))
+}
+
+func (UnimplementedDataServiceHandler) ListHotels(context.Context, *connect.Request[proto.ListHotelsRequest]) (*connect.Response[proto.ListHotelsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("data.DataService.ListHotels is not implemented"))
+}